@@ -0,0 +1,299 @@
+// Package a11y checks trees of html.Node for common accessibility problems.
+//
+// It is designed to run in unit tests:
+//
+//	if issues := a11y.Check(page); len(issues) > 0 {
+//	    t.Error(issues)
+//	}
+package a11y
+
+import (
+	"fmt"
+	stdhtml "html"
+	"sort"
+	"strconv"
+	"strings"
+
+	. "github.com/gohtml/elements"
+	"github.com/gohtml/html"
+)
+
+// Rule IDs of issues.
+const (
+	// An IMG without an alt attribute. Use an empty alt for decorative images.
+	RuleImgAlt = "img-alt"
+	// A form control without a label.
+	RuleLabel = "label"
+	// A heading more than one level deeper than the previous one.
+	RuleHeadingOrder = "heading-order"
+	// A link without an accessible name.
+	RuleLinkName = "link-name"
+	// A TH without a scope attribute.
+	RuleThScope = "th-scope"
+	// An id used by more than one element.
+	RuleDuplicateID = "duplicate-id"
+	// An HTML element without a lang attribute.
+	RuleHtmlLang = "html-lang"
+	// An invalid role, an unknown aria-* attribute, or a reference to a missing id.
+	RuleAria = "aria"
+)
+
+// Issue is an accessibility problem found by Check.
+type Issue struct {
+	Rule string
+	// The path of the node, e.g. "html>body[1]>nav[0]>ul[0]>li[2]>a[0]".
+	// Indexes are the positions in the children of the parents, starting from 0.
+	Path    string
+	Message string
+}
+
+func (is Issue) String() string {
+	return fmt.Sprintf("%s: %s: %s", is.Path, is.Rule, is.Message)
+}
+
+type attrNode interface {
+	GetAttr(name string) (string, bool)
+}
+
+func attr(nd html.Node, name string) (string, bool) {
+	if a, ok := nd.(attrNode); ok {
+		return a.GetAttr(name)
+	}
+	return "", false
+}
+
+func nonEmptyAttr(nd html.Node, name string) bool {
+	v, _ := attr(nd, name)
+	return strings.TrimSpace(v) != ""
+}
+
+// textContent returns the text of nd and its descendants, including the alts
+// of IMGs.
+func textContent(nd html.Node) string {
+	var b strings.Builder
+	var visit func(nd html.Node)
+	visit = func(nd html.Node) {
+		switch nd.Type() {
+		case TextType:
+			if t, ok := nd.(html.HTMLNode); ok {
+				b.WriteString(stdhtml.UnescapeString(string(t)))
+			}
+			return
+		case IMGTag:
+			alt, _ := attr(nd, "alt")
+			b.WriteString(alt)
+			return
+		}
+		if label, ok := attr(nd, "aria-label"); ok {
+			b.WriteString(label)
+			return
+		}
+		for _, c := range html.ChildNodes(nd) {
+			visit(c)
+		}
+	}
+	visit(nd)
+	return strings.TrimSpace(b.String())
+}
+
+func nodeName(nd html.Node) string {
+	switch n := nd.(type) {
+	case *html.Html:
+		return "html"
+	case interface{ Name() html.HTMLNode }:
+		return string(n.Name())
+	}
+	if tp := nd.Type(); tp >= 0 && int(tp) < len(TagNames) {
+		return TagNames[tp]
+	}
+	return fmt.Sprintf("%T", nd)
+}
+
+type checker struct {
+	issues []Issue
+	// Paths of elements keyed by ids.
+	ids map[string][]string
+	// Values of "for" of LABELs.
+	labelFors map[string]bool
+	// ids referenced by aria-labelledby and aria-describedby, with the paths.
+	refs         []idRef
+	lastHeading  int
+	inLabelDepth int
+}
+
+type idRef struct {
+	id, path, attr string
+}
+
+func (c *checker) report(rule, path, format string, args ...interface{}) {
+	c.issues = append(c.issues, Issue{Rule: rule, Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// collect collects the ids and the "for"s of LABELs.
+func (c *checker) collect(nd html.Node, path string) {
+	if id, ok := attr(nd, "id"); ok && id != "" {
+		c.ids[id] = append(c.ids[id], path)
+	}
+	if nd.Type() == LABELTag {
+		if f, ok := attr(nd, "for"); ok {
+			c.labelFors[f] = true
+		}
+	}
+	for i, child := range html.ChildNodes(nd) {
+		c.collect(child, childPath(path, child, i))
+	}
+}
+
+func childPath(path string, nd html.Node, i int) string {
+	return path + ">" + nodeName(nd) + "[" + strconv.Itoa(i) + "]"
+}
+
+func (c *checker) check(nd html.Node, path string) {
+	switch tp := nd.Type(); tp {
+	case IMGTag:
+		if _, ok := attr(nd, "alt"); !ok {
+			c.report(RuleImgAlt, path, "IMG has no alt attribute")
+		}
+
+	case INPUTTag, SELECTTag, TEXTAREATag:
+		c.checkLabel(nd, path)
+
+	case H1Tag, H2Tag, H3Tag, H4Tag, H5Tag, H6Tag:
+		level := int(tp-H1Tag) + 1
+		switch {
+		case c.lastHeading == 0 && level > 1:
+			c.report(RuleHeadingOrder, path, "H%d is the first heading", level)
+		case level > c.lastHeading+1:
+			c.report(RuleHeadingOrder, path, "H%d follows H%d", level, c.lastHeading)
+		}
+		c.lastHeading = level
+
+	case ATag:
+		if textContent(nd) == "" && !nonEmptyAttr(nd, "aria-labelledby") && !nonEmptyAttr(nd, "title") {
+			c.report(RuleLinkName, path, "link has no accessible name")
+		}
+
+	case THTag:
+		if !nonEmptyAttr(nd, "scope") {
+			c.report(RuleThScope, path, "TH has no scope attribute")
+		}
+
+	case HTMLTag:
+		if !nonEmptyAttr(nd, "lang") {
+			c.report(RuleHtmlLang, path, "HTML has no lang attribute")
+		}
+	}
+	c.checkAria(nd, path)
+
+	if nd.Type() == LABELTag {
+		c.inLabelDepth++
+		defer func() { c.inLabelDepth-- }()
+	}
+	for i, child := range html.ChildNodes(nd) {
+		c.check(child, childPath(path, child, i))
+	}
+}
+
+func (c *checker) checkLabel(nd html.Node, path string) {
+	if nd.Type() == INPUTTag {
+		switch tp, _ := attr(nd, "type"); tp {
+		case "hidden", "submit", "button", "reset", "image":
+			return
+		}
+	}
+	if c.inLabelDepth > 0 || nonEmptyAttr(nd, "aria-label") || nonEmptyAttr(nd, "aria-labelledby") || nonEmptyAttr(nd, "title") {
+		return
+	}
+	if id, _ := attr(nd, "id"); id != "" && c.labelFors[id] {
+		return
+	}
+	c.report(RuleLabel, path, "%s has no associated LABEL", strings.ToUpper(nodeName(nd)))
+}
+
+func (c *checker) checkAria(nd html.Node, path string) {
+	a, ok := nd.(interface {
+		EachAttr(fn func(name, value string))
+	})
+	if !ok {
+		return
+	}
+	a.EachAttr(func(name, value string) {
+		switch {
+		case name == "role":
+			for _, role := range strings.Fields(value) {
+				if !validRole(role) {
+					c.report(RuleAria, path, "invalid role %q", role)
+				}
+			}
+		case strings.HasPrefix(name, "aria-"):
+			if !validAriaAttrs[name] {
+				c.report(RuleAria, path, "unknown attribute %q", name)
+				return
+			}
+			if name == "aria-labelledby" || name == "aria-describedby" || name == "aria-controls" || name == "aria-owns" {
+				for _, id := range strings.Fields(value) {
+					c.refs = append(c.refs, idRef{id: id, path: path, attr: name})
+				}
+			}
+		}
+	})
+}
+
+// Check checks the tree rooted at root and returns the issues found. If root
+// is not an *html.Html, the lang of the document is not checked.
+func Check(root html.Node) []Issue {
+	c := &checker{
+		ids:       make(map[string][]string),
+		labelFors: make(map[string]bool),
+	}
+	path := nodeName(root)
+	c.collect(root, path)
+	c.check(root, path)
+
+	for _, ref := range c.refs {
+		if len(c.ids[ref.id]) == 0 {
+			c.report(RuleAria, ref.path, "%s references missing id %q", ref.attr, ref.id)
+		}
+	}
+	ids := make([]string, 0, len(c.ids))
+	for id := range c.ids {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if paths := c.ids[id]; len(paths) > 1 {
+			c.report(RuleDuplicateID, paths[1], "id %q is also used by %s", id, paths[0])
+		}
+	}
+	return c.issues
+}
+
+var validRoles = map[string]bool{}
+
+// validRole returns true for roles of WAI-ARIA 1.2, and of the DPUB-ARIA and
+// Graphics ARIA modules.
+func validRole(role string) bool {
+	return validRoles[role] || strings.HasPrefix(role, "doc-") || strings.HasPrefix(role, "graphics-")
+}
+
+var validAriaAttrs = map[string]bool{}
+
+func init() {
+	for _, role := range strings.Fields(`alert alertdialog application article banner button cell checkbox
+		columnheader combobox complementary contentinfo definition dialog directory document feed figure
+		form grid gridcell group heading img link list listbox listitem log main marquee math menu menubar
+		menuitem menuitemcheckbox menuitemradio navigation none note option presentation progressbar radio
+		radiogroup region row rowgroup rowheader scrollbar search searchbox separator slider spinbutton
+		status switch tab table tablist tabpanel term textbox timer toolbar tooltip tree treegrid treeitem
+		generic paragraph blockquote caption code deletion insertion emphasis strong subscript superscript
+		time meter mark`) {
+		validRoles[role] = true
+	}
+	for _, name := range strings.Fields(`activedescendant atomic autocomplete busy checked colcount
+		colindex colspan controls current describedby details disabled dropeffect errormessage expanded
+		flowto grabbed haspopup hidden invalid keyshortcuts label labelledby level live modal multiline
+		multiselectable orientation owns placeholder posinset pressed readonly relevant required
+		roledescription rowcount rowindex rowspan selected setsize sort valuemax valuemin valuenow valuetext`) {
+		validAriaAttrs["aria-"+name] = true
+	}
+}
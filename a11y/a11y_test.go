@@ -0,0 +1,101 @@
+package a11y
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golangplus/testing/assert"
+
+	"github.com/gohtml/html"
+)
+
+func rules(issues []Issue) []string {
+	var rs []string
+	for _, is := range issues {
+		rs = append(rs, is.Rule)
+	}
+	return rs
+}
+
+func TestCheck_clean(t *testing.T) {
+	h := html.HTML("en")
+	h.Body().Child(
+		html.H1(html.T("Title")),
+		html.H2(html.T("Section")),
+		html.IMG("a.png", "A"),
+		html.LABEL("name", html.T("Name")),
+		html.INPUT("text", "name", "").ID("name"),
+		html.LABEL("", html.T("Agree"), html.INPUT("checkbox", "agree", "")),
+		html.INPUT("hidden", "token", "x"),
+		html.A("/", html.IMG("home.png", "Home")),
+		html.NAV(html.T("x")).Attr("aria-label", "Main").Attr("role", "navigation"),
+		html.TableOf([]int{1}, html.Column[int]{Title: "N"}),
+	)
+	assert.Equal(t, "issues", Check(h), []Issue(nil))
+}
+
+func TestCheck(t *testing.T) {
+	h := html.HTML("")
+	h.Body().Child(
+		html.H1(html.T("Title")),
+		html.H3(html.T("Skipped")),
+		html.IMG("a.png", ""),
+		html.INPUT("text", "name", ""),
+		html.A("/"),
+		html.TABLE(html.TR(html.TH(html.T("N")))),
+		html.DIV().Attr("id", "x").Attr("role", "bogus").Attr("aria-labeledby", "y"),
+		html.SPAN().Attr("id", "x").Attr("aria-describedby", "missing"),
+	)
+	assert.Equal(t, "rules", rules(Check(h)), []string{
+		RuleHtmlLang, RuleHeadingOrder, RuleImgAlt, RuleLabel, RuleLinkName, RuleThScope,
+		RuleAria, RuleAria, RuleAria, RuleDuplicateID,
+	})
+}
+
+func TestCheck_roles(t *testing.T) {
+	div := html.DIV(
+		html.P(html.T("x")).Attr("role", "generic"),
+		html.SPAN().Attr("role", "mark"),
+		html.DIV().Attr("role", "doc-chapter"),
+		html.SPAN().Attr("role", "graphics-symbol"),
+		html.SPAN().Attr("role", "doc"),
+	)
+	assert.Equal(t, "issues", Check(div), []Issue{
+		{Rule: RuleAria, Path: "div>span[4]", Message: `invalid role "doc"`},
+	})
+}
+
+func TestCheck_firstHeading(t *testing.T) {
+	assert.Equal(t, "issues", Check(html.DIV(html.H2(html.T("x")))), []Issue{
+		{Rule: RuleHeadingOrder, Path: "div>h2[0]", Message: "H2 is the first heading"},
+	})
+}
+
+func TestCheck_duplicateIDs(t *testing.T) {
+	div := html.DIV(
+		html.SPAN().Attr("id", "c"), html.SPAN().Attr("id", "b"), html.SPAN().Attr("id", "a"),
+		html.SPAN().Attr("id", "c"), html.SPAN().Attr("id", "b"), html.SPAN().Attr("id", "a"),
+	)
+	assert.Equal(t, "issues", Check(div), []Issue{
+		{Rule: RuleDuplicateID, Path: "div>span[5]", Message: `id "a" is also used by div>span[2]`},
+		{Rule: RuleDuplicateID, Path: "div>span[4]", Message: `id "b" is also used by div>span[1]`},
+		{Rule: RuleDuplicateID, Path: "div>span[3]", Message: `id "c" is also used by div>span[0]`},
+	})
+}
+
+func TestCheck_static(t *testing.T) {
+	div := html.DIV(html.Static(html.UL(html.LI(html.IMG("a.png", ""))), html.RenderOptions{}))
+	assert.Equal(t, "issues", Check(div), []Issue{
+		{Rule: RuleImgAlt, Path: "div>ul[0]>li[0]>img[0]", Message: "IMG has no alt attribute"},
+	})
+}
+
+func ExampleCheck() {
+	body := html.DIV(html.UL(html.LI(html.A("/home")), html.LI(html.IMG("logo.png", ""))))
+	for _, is := range Check(body) {
+		fmt.Println(is)
+	}
+	// OUTPUT:
+	// div>ul[0]>li[0]>a[0]: link-name: link has no accessible name
+	// div>ul[0]>li[1]>img[0]: img-alt: IMG has no alt attribute
+}
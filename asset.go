@@ -0,0 +1,276 @@
+package html
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	stdhtml "html"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	. "github.com/gohtml/elements"
+	"github.com/gohtml/utils"
+)
+
+// The scheme of URLs referencing assets by logical names.
+const assetScheme = "asset:"
+
+// Asset returns a URL referencing an asset by its logical name, e.g.
+// Asset("js/main.js"). The URL is resolved by RenderOptions.Assets when
+// rendered as the value of a src, href, action, poster or data attribute, or
+// as a candidate of a srcset. Render reports an error if the asset is unknown.
+func Asset(name string) URL {
+	return URL(assetScheme + name)
+}
+
+type assetEntry struct {
+	// The fingerprinted path relative to the prefix.
+	file string
+	// Logical names of imported chunks.
+	imports []string
+	// Paths of CSS files relative to the prefix.
+	css []string
+	// The hex SHA-256 of the content. Set by NewAssetManifest only.
+	hash string
+}
+
+// AssetManifest maps logical names of assets to fingerprinted URLs. Set it to
+// RenderOptions.Assets to resolve URLs returned by Asset.
+//
+// When an Html is rendered, every module SCRIPT of an asset adds modulepreload
+// LINKs of the chunks it imports, and stylesheet LINKs of its CSS, into the
+// head.
+type AssetManifest struct {
+	// The prefix of all URLs, e.g. "/static/" or "https://cdn.example.com/".
+	Prefix URL
+
+	assets map[string]*assetEntry
+	// Original paths keyed by fingerprinted paths, used by Handler.
+	origins map[string]string
+}
+
+// NewAssetManifest creates an AssetManifest of the files in fsys. Logical names
+// are the paths of the files, and fingerprinted paths contain hashes of the
+// contents, e.g. "js/main.js" becomes "js/main.1a2b3c4d.js".
+func NewAssetManifest(fsys fs.FS, prefix URL) (*AssetManifest, error) {
+	m := &AssetManifest{
+		Prefix:  prefix,
+		assets:  make(map[string]*assetEntry),
+		origins: make(map[string]string),
+	}
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		content, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(content)
+		ext := path.Ext(name)
+		file := strings.TrimSuffix(name, ext) + "." + hex.EncodeToString(sum[:4]) + ext
+
+		m.assets[name] = &assetEntry{file: file, hash: hex.EncodeToString(sum[:])}
+		m.origins[file] = name
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ParseViteManifest creates an AssetManifest from the manifest.json generated
+// by Vite (build.manifest). Logical names are the keys of the manifest, i.e.
+// source paths like "src/main.ts". Manifests of other bundlers, e.g. esbuild,
+// can be used if converted into the same format.
+func ParseViteManifest(data []byte, prefix URL) (*AssetManifest, error) {
+	var chunks map[string]struct {
+		File    string   `json:"file"`
+		Imports []string `json:"imports"`
+		CSS     []string `json:"css"`
+	}
+	if err := json.Unmarshal(data, &chunks); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %v", err)
+	}
+
+	m := &AssetManifest{
+		Prefix: prefix,
+		assets: make(map[string]*assetEntry, len(chunks)),
+	}
+	for name, c := range chunks {
+		if c.File == "" {
+			return nil, fmt.Errorf("invalid manifest: no file of %q", name)
+		}
+		m.assets[name] = &assetEntry{
+			file:    c.File,
+			imports: c.Imports,
+			css:     c.CSS,
+		}
+	}
+	for name, a := range m.assets {
+		for _, imp := range a.imports {
+			if _, ok := m.assets[imp]; !ok {
+				return nil, fmt.Errorf("invalid manifest: %q imports unknown chunk %q", name, imp)
+			}
+		}
+	}
+	return m, nil
+}
+
+// URL returns the fingerprinted URL of an asset.
+func (m *AssetManifest) URL(name string) (URL, error) {
+	a, ok := m.assets[name]
+	if !ok {
+		return "", fmt.Errorf("unknown asset %q", name)
+	}
+	return m.Prefix + URL(a.file), nil
+}
+
+// deps returns the URLs of the chunks imported by an asset, directly or
+// indirectly, and of the CSS files of them.
+func (m *AssetManifest) deps(name string) (chunks, css []URL) {
+	visited := make(map[string]bool)
+	var visit func(name string, root bool)
+	visit = func(name string, root bool) {
+		if visited[name] {
+			return
+		}
+		visited[name] = true
+
+		a, ok := m.assets[name]
+		if !ok {
+			return
+		}
+		if !root {
+			chunks = append(chunks, m.Prefix+URL(a.file))
+		}
+		for _, f := range a.css {
+			css = append(css, m.Prefix+URL(f))
+		}
+		for _, imp := range a.imports {
+			visit(imp, false)
+		}
+	}
+	visit(name, true)
+	return chunks, css
+}
+
+// contributeHead puts the dependencies of nd into hs if it is a module SCRIPT
+// of an asset.
+func (m *AssetManifest) contributeHead(nd Node, hs func() *HeadSet) {
+	v := voidOf(nd)
+	if v == nil || v.tagType != SCRIPTTag {
+		return
+	}
+	if tp, _ := v.attributes.get("type"); tp != "module" {
+		return
+	}
+	src, _ := v.attributes.get("src")
+	if !strings.HasPrefix(string(src), assetScheme) {
+		return
+	}
+
+	chunks, css := m.deps(strings.TrimPrefix(string(src), assetScheme))
+	for _, u := range chunks {
+		hs().Put("link:modulepreload "+string(u), LINK(u, "modulepreload"))
+	}
+	for _, u := range css {
+		hs().Stylesheet(u)
+	}
+}
+
+// Handler returns a handler serving the files of fsys, from which the manifest
+// was created by NewAssetManifest, at their fingerprinted paths relative to the
+// URL path of the request. Since paths change with contents, files are cached
+// by clients for long. ETags are the hashes of the contents, so conditional and
+// range requests are supported.
+func (m *AssetManifest) Handler(fsys fs.FS) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name, ok := m.origins[strings.TrimPrefix(r.URL.Path, "/")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		content, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if ct := mime.TypeByExtension(path.Ext(name)); ct != "" {
+			w.Header().Set("Content-Type", ct)
+		}
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		w.Header().Set("ETag", `"`+m.assets[name].hash+`"`)
+		http.ServeContent(w, r, name, time.Time{}, bytes.NewReader(content))
+	})
+}
+
+// Attributes whose values may be URLs returned by Asset.
+var assetAttrs = map[HTMLNode]bool{
+	"src": true, "href": true, "srcset": true, "action": true, "poster": true, "data": true,
+}
+
+// resolvesAssets returns true if the value of the attribute name references
+// assets, i.e. name is a URL attribute and the value, or a candidate of a
+// srcset, starts with "asset:".
+func resolvesAssets(name, value HTMLNode) bool {
+	if !assetAttrs[name] || !strings.Contains(string(value), assetScheme) {
+		return false
+	}
+	if name != "srcset" {
+		return strings.HasPrefix(string(value), assetScheme)
+	}
+	for _, c := range strings.Split(string(value), ",") {
+		if strings.HasPrefix(strings.TrimLeft(c, " \t\n\r\f"), assetScheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveAssets replaces the references to assets in the value of the
+// attribute name, which is checked by resolvesAssets, with their URLs. Unknown
+// assets are reported.
+func (opt *RenderOptions) resolveAssets(name, value HTMLNode) HTMLNode {
+	if name != "srcset" {
+		return opt.resolveAsset(string(value))
+	}
+
+	// Candidates are separated by commas, and URLs by whitespace from their
+	// descriptors.
+	cands := strings.Split(string(value), ",")
+	for i, c := range cands {
+		u := strings.TrimLeft(c, " \t\n\r\f")
+		if !strings.HasPrefix(u, assetScheme) {
+			continue
+		}
+		desc := ""
+		if end := strings.IndexAny(u, " \t\n\r\f"); end >= 0 {
+			u, desc = u[:end], u[end:]
+		}
+		cands[i] = c[:len(c)-len(u)-len(desc)] + string(opt.resolveAsset(u)) + desc
+	}
+	return HTMLNode(strings.Join(cands, ","))
+}
+
+// resolveAsset returns the escaped URL of an escaped "asset:" reference.
+func (opt *RenderOptions) resolveAsset(ref string) HTMLNode {
+	name := stdhtml.UnescapeString(strings.TrimPrefix(ref, assetScheme))
+	if opt.Assets == nil {
+		opt.fail(fmt.Errorf("no asset manifest to resolve %q", name))
+		return HTMLNode(ref)
+	}
+	u, err := opt.Assets.URL(name)
+	if err != nil {
+		opt.fail(err)
+		return HTMLNode(ref)
+	}
+	return HTMLNode(utils.EscapeAttr(string(u)))
+}
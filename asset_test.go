@@ -0,0 +1,116 @@
+package html
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/golangplus/testing/assert"
+
+	. "github.com/gohtml/elements"
+)
+
+func TestNewAssetManifest(t *testing.T) {
+	fsys := fstest.MapFS{
+		"js/main.js": {Data: []byte("alert(1)")},
+		"logo.png":   {Data: []byte("PNG")},
+	}
+	m, err := NewAssetManifest(fsys, "/static/")
+	assert.NoError(t, err)
+
+	u, err := m.URL("js/main.js")
+	assert.NoError(t, err)
+	assert.Equal(t, "u", u, URL("/static/js/main.6e11c72f.js"))
+	_, err = m.URL("missing.js")
+	assert.True(t, "err != nil", err != nil)
+
+	w := httptest.NewRecorder()
+	m.Handler(fsys).ServeHTTP(w, httptest.NewRequest("GET", "/js/main.6e11c72f.js", nil))
+	assert.Equal(t, "code", w.Code, http.StatusOK)
+	assert.Equal(t, "body", w.Body.String(), "alert(1)")
+	assert.Equal(t, "Content-Type", w.Header().Get("Content-Type"), "text/javascript; charset=utf-8")
+	etag := w.Header().Get("ETag")
+	assert.Equal(t, "ETag", etag, `"6e11c72f`+etag[9:])
+	assert.Equal(t, "len(ETag)", len(etag), 66)
+
+	r := httptest.NewRequest("GET", "/js/main.6e11c72f.js", nil)
+	r.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	m.Handler(fsys).ServeHTTP(w, r)
+	assert.Equal(t, "code", w.Code, http.StatusNotModified)
+
+	r = httptest.NewRequest("GET", "/js/main.6e11c72f.js", nil)
+	r.Header.Set("Range", "bytes=0-4")
+	w = httptest.NewRecorder()
+	m.Handler(fsys).ServeHTTP(w, r)
+	assert.Equal(t, "code", w.Code, http.StatusPartialContent)
+	assert.Equal(t, "body", w.Body.String(), "alert")
+
+	w = httptest.NewRecorder()
+	m.Handler(fsys).ServeHTTP(w, httptest.NewRequest("GET", "/js/main.js", nil))
+	assert.Equal(t, "code", w.Code, http.StatusNotFound)
+}
+
+func TestRender_unknownAsset(t *testing.T) {
+	m, err := ParseViteManifest([]byte(`{"a.png": {"file": "a-123.png"}}`), "")
+	assert.NoError(t, err)
+
+	var b bytes.Buffer
+	err = Render(&b, IMG(Asset("b.png"), "B"), RenderOptions{Assets: m})
+	assert.Equal(t, "err", fmt.Sprint(err), `unknown asset "b.png"`)
+	assert.Equal(t, "b", b.String(), "")
+
+	err = Render(&b, IMG(Asset("a.png"), "A"), RenderOptions{})
+	assert.Equal(t, "err", fmt.Sprint(err), `no asset manifest to resolve "a.png"`)
+
+	assert.NoError(t, Render(&b, IMG(Asset("a.png"), "A"), RenderOptions{Assets: m}))
+	assert.Equal(t, "b", b.String(), `<img src="a-123.png" alt="A">`)
+}
+
+func TestRender_assetInText(t *testing.T) {
+	var b bytes.Buffer
+	img := IMG("/x.png", "Brand asset: logo").Attr("title", "asset:logo").Attr("data-x", "a, asset:b")
+	assert.NoError(t, Render(&b, img, DefaultOptions))
+	assert.Equal(t, "b", b.String(), `<img src="/x.png" alt="Brand asset: logo" title="asset:logo" data-x="a, asset:b">`)
+
+	b.Reset()
+	assert.NoError(t, Render(&b, A("/asset:x", T("x")), DefaultOptions))
+	assert.Equal(t, "b", b.String(), `<a href="/asset:x">x</a>`)
+}
+
+func TestRender_assetSrcset(t *testing.T) {
+	m, err := ParseViteManifest([]byte(`{"a.png": {"file": "a-1.png"}, "b.png": {"file": "b-2.png"}}`), "/s/")
+	assert.NoError(t, err)
+
+	var b bytes.Buffer
+	img := IMG(Asset("a.png"), "A").Attr("srcset", "asset:a.png 1x, /c.png 3x,asset:b.png 2x")
+	assert.NoError(t, Render(&b, img, RenderOptions{Assets: m}))
+	assert.Equal(t, "b", b.String(), `<img src="/s/a-1.png" alt="A" srcset="/s/a-1.png 1x, /c.png 3x,/s/b-2.png 2x">`)
+}
+
+func TestParseViteManifest_invalid(t *testing.T) {
+	_, err := ParseViteManifest([]byte(`{"a.js": {"file": "a.js", "imports": ["b.js"]}}`), "")
+	assert.Equal(t, "err", fmt.Sprint(err), `invalid manifest: "a.js" imports unknown chunk "b.js"`)
+}
+
+func ExampleParseViteManifest() {
+	m, _ := ParseViteManifest([]byte(`{
+		"src/main.ts": {"file": "assets/main-4f2a.js", "isEntry": true, "imports": ["_vendor.js"], "css": ["assets/main-9c1b.css"]},
+		"_vendor.js": {"file": "assets/vendor-77d0.js", "imports": ["_shared.js"]},
+		"_shared.js": {"file": "assets/shared-0a1b.js"}
+	}`), "https://cdn.example.com/")
+
+	h := HTML("")
+	h.Body().Child(SCRIPT(Asset("src/main.ts"), "").Attr("type", "module"))
+	var b bytes.Buffer
+	if err := Render(&b, h, RenderOptions{SortAttr: true, Assets: m}); err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(b.String())
+	// OUTPUT:
+	// <!DOCTYPE html>
+	// <meta charset="utf-8"><link href="https://cdn.example.com/assets/vendor-77d0.js" rel="modulepreload"><link href="https://cdn.example.com/assets/shared-0a1b.js" rel="modulepreload"><link href="https://cdn.example.com/assets/main-9c1b.css" rel="stylesheet" type="text/css"><body><script src="https://cdn.example.com/assets/main-4f2a.js" type="module"></script>
+}
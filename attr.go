@@ -1,7 +1,8 @@
 package html
 
 import (
-	"github.com/golangplus/sort"
+	stdhtml "html"
+	"strings"
 )
 
 type attrInfo struct {
@@ -11,27 +12,113 @@ type attrInfo struct {
 
 type Attributes []attrInfo
 
+// AttrOrder defines the order in which attributes of an element are exported.
+type AttrOrder int
+
+const (
+	// Attributes are exported in the order they were first set.
+	InsertionOrder AttrOrder = iota
+	// Attributes are exported sorted by their names.
+	AlphabeticalOrder
+	// The id attribute is exported first, then the class attribute, then others
+	// sorted by their names.
+	CanonicalOrder
+)
+
+// The number of attributes that can be ordered without allocations.
+const attrOrderBufSize = 16
+
+// WriteTo exports the attributes. If sortAttr is true, attributes are exported
+// sorted by their names. The attributes themselves are never modified.
 func (attrs Attributes) WriteTo(b Writer, sortAttr bool) {
+	order := InsertionOrder
+	if sortAttr {
+		order = AlphabeticalOrder
+	}
+	attrs.writeOrdered(b, order, nil)
+}
+
+// writeOrdered exports the attributes in the specified order. With CanonicalOrder
+// the id attribute is skipped since it is exported by the Void before the classes.
+// If opt is not nil, asset references in values are resolved.
+func (attrs Attributes) writeOrdered(b Writer, order AttrOrder, opt *RenderOptions) {
 	if len(attrs) == 0 {
 		return
 	}
 
-	if sortAttr && len(attrs) > 1 {
-		sortp.SortF(len(attrs), func(i, j int) bool {
-			return attrs[i].name < attrs[j].name
-		}, func(i, j int) {
-			attrs[i], attrs[j] = attrs[j], attrs[i]
-		})
+	if order == InsertionOrder || len(attrs) == 1 && order == AlphabeticalOrder {
+		for i := range attrs {
+			attrs[i].writeTo(b, opt)
+		}
+		return
 	}
 
+	// Sort indexes instead of the attributes so that the node is never mutated
+	// during rendering.
+	var buf [attrOrderBufSize]int
+	idx := buf[:0]
+	if len(attrs) > len(buf) {
+		idx = make([]int, 0, len(attrs))
+	}
 	for i := range attrs {
-		b.WriteByte(' ')
-		attrs[i].name.WriteRaw(b)
-		if len(attrs[i].value) > 0 {
-			b.WriteString(`="`)
-			attrs[i].value.WriteRaw(b)
-			b.WriteByte('"')
+		if order == CanonicalOrder && attrs[i].name == "id" {
+			continue
+		}
+		// Insertion sort, the number of attributes is usually small.
+		j := len(idx)
+		idx = append(idx, i)
+		for ; j > 0 && attrs[i].name < attrs[idx[j-1]].name; j-- {
+			idx[j] = idx[j-1]
+		}
+		idx[j] = i
+	}
+
+	for _, i := range idx {
+		attrs[i].writeTo(b, opt)
+	}
+}
+
+// eachAttr calls fn with the names and the unescaped values of the class and
+// the attributes, in insertion order except that the class comes first.
+func eachAttr(attrs Attributes, classes htmlNodeSet, fn func(name, value string)) {
+	if len(classes) > 0 {
+		cls := make([]string, len(classes))
+		for i, c := range classes {
+			cls[i] = stdhtml.UnescapeString(string(c))
+		}
+		fn("class", strings.Join(cls, " "))
+	}
+	for _, attr := range attrs {
+		fn(string(attr.name), stdhtml.UnescapeString(string(attr.value)))
+	}
+}
+
+// getAttr returns the unescaped value of an attribute including the class.
+func getAttr(attrs Attributes, classes htmlNodeSet, name string) (string, bool) {
+	if name != "class" {
+		return attrs.Get(name)
+	}
+	if len(classes) == 0 {
+		return "", false
+	}
+	var value string
+	eachAttr(nil, classes, func(_, v string) {
+		value = v
+	})
+	return value, true
+}
+
+func (attr *attrInfo) writeTo(b Writer, opt *RenderOptions) {
+	b.WriteByte(' ')
+	attr.name.WriteRaw(b)
+	if len(attr.value) > 0 {
+		b.WriteString(`="`)
+		if opt != nil && resolvesAssets(attr.name, attr.value) {
+			opt.resolveAssets(attr.name, attr.value).WriteRaw(b)
+		} else {
+			attr.value.WriteRaw(b)
 		}
+		b.WriteByte('"')
 	}
 }
 
@@ -44,6 +131,22 @@ func (attrs Attributes) index(name HTMLNode) int {
 	return -1
 }
 
+// get returns the escaped value of an attribute.
+func (attrs Attributes) get(name HTMLNode) (HTMLNode, bool) {
+	if i := attrs.index(name); i >= 0 {
+		return attrs[i].value, true
+	}
+	return "", false
+}
+
+// Get returns the unescaped value of an attribute.
+func (attrs Attributes) Get(name string) (string, bool) {
+	if v, ok := attrs.get(HTMLNode(name)); ok {
+		return stdhtml.UnescapeString(string(v)), true
+	}
+	return "", false
+}
+
 func (attrs *Attributes) Put(name, value HTMLNode) {
 	i := attrs.index(name)
 	if i >= 0 {
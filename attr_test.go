@@ -21,3 +21,23 @@ func TestAttributes(t *testing.T) {
 	attrs.WriteTo(&b, true)
 	assert.Equal(t, "attrs", string(b), ` abc`)
 }
+
+func TestAttributes_noMutation(t *testing.T) {
+	var attrs Attributes
+	attrs.Put("src", "a.png")
+	attrs.Put("alt", "A")
+	attrs.Put("id", "logo")
+
+	var b bytesp.ByteSlice
+	attrs.writeOrdered(&b, AlphabeticalOrder, nil)
+	assert.Equal(t, "attrs", string(b), ` alt="A" id="logo" src="a.png"`)
+	assert.Equal(t, "attrs[0].name", attrs[0].name, HTMLNode("src"))
+
+	b = nil
+	attrs.writeOrdered(&b, InsertionOrder, nil)
+	assert.Equal(t, "attrs", string(b), ` src="a.png" alt="A" id="logo"`)
+
+	b = nil
+	attrs.writeOrdered(&b, CanonicalOrder, nil)
+	assert.Equal(t, "attrs", string(b), ` alt="A" src="a.png"`)
+}
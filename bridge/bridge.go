@@ -0,0 +1,186 @@
+// Package bridge converts between html.Node trees and the types of
+// html/template and golang.org/x/net/html, so that pages can be migrated one
+// by one.
+//
+// A Node can be used in a template by rendering it into a template.HTML, e.g.
+// with a function like
+//
+//	template.FuncMap{"node": func(nd html.Node) (template.HTML, error) {
+//		return bridge.TemplateHTML(nd, html.DefaultOptions)
+//	}}
+//
+// and the output of a template can be used as a Node by Execute.
+package bridge
+
+import (
+	"bytes"
+	"html/template"
+
+	"github.com/gohtml/elements"
+	"github.com/gohtml/html"
+	"github.com/gohtml/html/internal/tags"
+	xhtml "golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// TemplateHTML renders nd into a template.HTML, which is inserted into the
+// output of html/template as it is. Like html.Render, it returns the first
+// error reported during rendering.
+func TemplateHTML(nd html.Node, opt html.RenderOptions) (template.HTML, error) {
+	var b bytes.Buffer
+	if err := html.Render(&b, nd, opt); err != nil {
+		return "", err
+	}
+	return template.HTML(b.String()), nil
+}
+
+// FromTemplateHTML returns h as an html.Raw. Like html/template, h is trusted
+// and written without escaping.
+func FromTemplateHTML(h template.HTML) html.Node {
+	return html.Raw(h)
+}
+
+// Execute executes t with data and returns the output as an html.Raw.
+func Execute(t *template.Template, data interface{}) (html.Node, error) {
+	var b bytes.Buffer
+	if err := t.Execute(&b, data); err != nil {
+		return nil, err
+	}
+	return html.Raw(b.String()), nil
+}
+
+// ExecuteTemplate is same as Execute but executes the template associated
+// with t that has the given name.
+func ExecuteTemplate(t *template.Template, name string, data interface{}) (html.Node, error) {
+	var b bytes.Buffer
+	if err := t.ExecuteTemplate(&b, name, data); err != nil {
+		return nil, err
+	}
+	return html.Raw(b.String()), nil
+}
+
+// Contexts for parsing elements which are not allowed in BODY, keyed by their
+// tag names.
+var fragmentContexts = map[string]atom.Atom{
+	"caption":  atom.Table,
+	"colgroup": atom.Table,
+	"thead":    atom.Table,
+	"tbody":    atom.Table,
+	"tfoot":    atom.Table,
+	"tr":       atom.Tbody,
+	"td":       atom.Tr,
+	"th":       atom.Tr,
+	"col":      atom.Colgroup,
+}
+
+// ToNetHTML renders nd and parses the result with golang.org/x/net/html. A
+// node of type HTMLTag, e.g. an *html.Html, is parsed as a document and the
+// result is the DocumentNode. Other nodes are parsed as a fragment in a BODY,
+// or in the element they are allowed in, e.g. a TABLE for a TBODY.
+//
+// Like html.Render, it returns the first error reported during rendering.
+func ToNetHTML(nd html.Node, opt html.RenderOptions) ([]*xhtml.Node, error) {
+	var b bytes.Buffer
+	if err := html.Render(&b, nd, opt); err != nil {
+		return nil, err
+	}
+
+	tp := nd.Type()
+	if tp == elements.HTMLTag {
+		doc, err := xhtml.Parse(&b)
+		if err != nil {
+			return nil, err
+		}
+		return []*xhtml.Node{doc}, nil
+	}
+
+	ctx := atom.Body
+	if tp >= 0 && int(tp) < len(elements.TagNames) {
+		if a, ok := fragmentContexts[elements.TagNames[tp]]; ok {
+			ctx = a
+		}
+	}
+	return xhtml.ParseFragment(&b, &xhtml.Node{
+		Type:     xhtml.ElementNode,
+		Data:     ctx.String(),
+		DataAtom: ctx,
+	})
+}
+
+// Elements whose texts are parsed without unescaping, so they are written as
+// they are.
+var rawTextTags = map[string]bool{
+	"iframe": true, "noembed": true, "noframes": true, "noscript": true, "plaintext": true,
+	"script": true, "style": true, "xmp": true,
+}
+
+// FromNetHTML converts a tree of golang.org/x/net/html into a Node. A
+// DocumentNode is converted into html.Nodes of its children, including the
+// doctype. HTML elements become *html.Element or *html.Void, and other
+// elements, e.g. SVG, MathML and custom elements, become *html.Foreign.
+// Comments and the doctype are kept as html.Raw.
+func FromNetHTML(n *xhtml.Node) html.Node {
+	switch n.Type {
+	case xhtml.DocumentNode:
+		return html.Nodes(childrenOf(n))
+
+	case xhtml.DoctypeNode, xhtml.CommentNode:
+		var b bytes.Buffer
+		xhtml.Render(&b, n)
+		return html.Raw(b.String())
+
+	case xhtml.TextNode:
+		if p := n.Parent; p != nil && p.Type == xhtml.ElementNode && p.Namespace == "" && rawTextTags[p.Data] {
+			return html.HTMLNode(n.Data)
+		}
+		return html.T(n.Data)
+
+	case xhtml.RawNode:
+		return html.Raw(n.Data)
+
+	case xhtml.ElementNode:
+		return elementOf(n)
+	}
+	return html.Nodes(nil)
+}
+
+func childrenOf(n *xhtml.Node) []html.Node {
+	var children []html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		children = append(children, FromNetHTML(c))
+	}
+	return children
+}
+
+func elementOf(n *xhtml.Node) html.Node {
+	tp, ok := tags.Types[n.Data]
+	if n.Namespace != "" || !ok {
+		f := html.FOREIGN(n.Data, childrenOf(n)...)
+		if n.Namespace == "" && n.FirstChild == nil {
+			// Custom elements can't be self-closing.
+			f.Child(html.HTMLNode(""))
+		}
+		for _, a := range n.Attr {
+			name := a.Key
+			if a.Namespace != "" {
+				name = a.Namespace + ":" + name
+			}
+			f.Attr(name, a.Val)
+		}
+		return f
+	}
+
+	if tags.Void[n.Data] {
+		v := html.NewVoid(tp)
+		for _, a := range n.Attr {
+			v.Attr(a.Key, a.Val)
+		}
+		return v
+	}
+
+	e := html.NewElement(tp, childrenOf(n)...)
+	for _, a := range n.Attr {
+		e.Attr(a.Key, a.Val)
+	}
+	return e
+}
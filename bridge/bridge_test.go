@@ -0,0 +1,122 @@
+package bridge
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+	"testing"
+
+	"github.com/golangplus/testing/assert"
+
+	"github.com/gohtml/html"
+	xhtml "golang.org/x/net/html"
+)
+
+func TestTemplateHTML(t *testing.T) {
+	tmpl := template.Must(template.New("page").Funcs(template.FuncMap{
+		"node": func(nd html.Node) (template.HTML, error) {
+			return TemplateHTML(nd, html.DefaultOptions)
+		},
+	}).Parse(`<main>{{.Title}} {{node .Body}}</main>`))
+
+	var b bytes.Buffer
+	assert.NoError(t, tmpl.Execute(&b, map[string]interface{}{
+		"Title": "<b>",
+		"Body":  html.P(html.T("a < b")),
+	}))
+	assert.Equal(t, "html", b.String(), `<main>&lt;b&gt; <p>a &lt; b</p></main>`)
+
+	_, err := TemplateHTML(html.IMG(html.Asset("missing.png"), "x"), html.RenderOptions{
+		Assets: &html.AssetManifest{},
+	})
+	assert.Error(t, err)
+}
+
+func TestExecute(t *testing.T) {
+	tmpl := template.Must(template.New("card").Parse(`<b>{{.}}</b>{{define "other"}}<i>{{.}}</i>{{end}}`))
+
+	nd, err := Execute(tmpl, "x&y")
+	assert.NoError(t, err)
+	assert.StringEqual(t, "card", html.NodeToHTMLNode(html.DIV(nd), html.DefaultOptions), `<div><b>x&amp;y</b></div>`)
+
+	nd, err = ExecuteTemplate(tmpl, "other", "z")
+	assert.NoError(t, err)
+	assert.StringEqual(t, "other", nd, `<i>z</i>`)
+
+	_, err = ExecuteTemplate(tmpl, "missing", nil)
+	assert.Error(t, err)
+
+	assert.StringEqual(t, "FromTemplateHTML", FromTemplateHTML(template.HTML("<hr>")), "<hr>")
+}
+
+func renderNet(t *testing.T, nodes []*xhtml.Node) string {
+	var b bytes.Buffer
+	for _, n := range nodes {
+		assert.NoError(t, xhtml.Render(&b, n))
+	}
+	return b.String()
+}
+
+func TestToNetHTML(t *testing.T) {
+	nodes, err := ToNetHTML(html.DIV(html.P(html.T("a")), html.P(html.T("b"))), html.DefaultOptions)
+	assert.NoError(t, err)
+	assert.Equal(t, "len", len(nodes), 1)
+	assert.Equal(t, "html", renderNet(t, nodes), `<div><p>a</p><p>b</p></div>`)
+
+	nodes, err = ToNetHTML(html.Nodes{html.TR(html.TD(html.T("1"))), html.TR(html.TD(html.T("2")))}, html.DefaultOptions)
+	assert.NoError(t, err)
+	assert.Equal(t, "rows", renderNet(t, nodes), `<tr><td>1</td></tr><tr><td>2</td></tr>`)
+
+	nodes, err = ToNetHTML(html.HTML("en"), html.DefaultOptions)
+	assert.NoError(t, err)
+	assert.Equal(t, "document", nodes[0].Type, xhtml.DocumentNode)
+	assert.Equal(t, "document html", renderNet(t, nodes),
+		`<!DOCTYPE html><html lang="en"><head><meta charset="utf-8"/></head><body></body></html>`)
+}
+
+func TestFromNetHTML(t *testing.T) {
+	doc, err := xhtml.Parse(strings.NewReader(`<!DOCTYPE html><title>T</title><!-- c -->` +
+		`<p class="x y" id=p>a &amp; b<br><svg viewBox="0 0 1 1"><use xlink:href="#i"/></svg>` +
+		`<my-el></my-el><script>if (a < b) {}</script>`))
+	assert.NoError(t, err)
+
+	assert.StringEqual(t, "html", html.NodeToHTMLNode(FromNetHTML(doc), html.RenderOptions{DisableOmit: true}),
+		`<!DOCTYPE html><html><head><title>T</title><!-- c --></head><body><p class="x y" id="p">a &amp; b<br>`+
+			`<svg viewBox="0 0 1 1"><use xlink:href="#i"/></svg><my-el></my-el><script>if (a < b) {}</script></p></body></html>`)
+}
+
+// roundTrip renders nd with the default options, which omit optional tags,
+// and converts the result back.
+func roundTrip(t *testing.T, nd html.Node) string {
+	nodes, err := ToNetHTML(nd, html.DefaultOptions)
+	assert.NoError(t, err)
+	var res html.Nodes
+	for _, n := range nodes {
+		res = append(res, FromNetHTML(n))
+	}
+	return string(html.NodeToHTMLNode(res, html.RenderOptions{DisableOmit: true}))
+}
+
+func TestRoundTrip(t *testing.T) {
+	tmpl := template.Must(template.New("item").Parse(`<li>{{.}}`))
+	item, err := Execute(tmpl, "b")
+	assert.NoError(t, err)
+	assert.Equal(t, "template", roundTrip(t, html.UL(html.LI(html.T("a")), item)),
+		`<ul><li>a</li><li>b</li></ul>`)
+
+	assert.Equal(t, "FromTemplateHTML", roundTrip(t, html.DIV(html.P(html.T("a")), FromTemplateHTML("<span>b</span>"))),
+		`<div><p>a</p><span>b</span></div>`)
+
+	doc, err := xhtml.Parse(strings.NewReader(`<!DOCTYPE html><html><head><title>T</title></head><!-- c --><body><!-- d --><p>a</p></body></html>`))
+	assert.NoError(t, err)
+	assert.Equal(t, "comments", roundTrip(t, html.Nodes(childrenOf(doc))[1]),
+		`<html><head><title>T</title></head><!-- c --><body><!-- d --><p>a</p></body></html>`)
+}
+
+func ExampleFromNetHTML() {
+	nodes, _ := xhtml.ParseFragment(strings.NewReader(`<ul><li><a href="/a">A</a><li>B</ul>`), nil)
+	fmt.Println(html.NodeToHTMLNode(FromNetHTML(nodes[0]), html.DefaultOptions))
+	// OUTPUT:
+	// <ul><li><a href="/a">A</a><li>B</ul>
+}
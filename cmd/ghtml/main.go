@@ -0,0 +1,62 @@
+// Command ghtml compiles .ghtml templates into Go files. See package
+// github.com/gohtml/html/ghtml for the template language.
+//
+// Usage:
+//
+//	ghtml file.ghtml...
+//
+// Each file.ghtml is compiled into file_ghtml.go in the same directory. It is
+// typically run by go generate:
+//
+//	//go:generate ghtml card.ghtml
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gohtml/html/ghtml"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: ghtml file.ghtml...")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	failed := false
+	for _, fn := range flag.Args() {
+		if err := compile(fn); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func compile(fn string) error {
+	src, err := os.ReadFile(fn)
+	if err != nil {
+		return err
+	}
+	// The generated file is in the same directory, so line directives use the
+	// base name.
+	out, err := ghtml.Generate(filepath.Base(fn), src)
+	if e, ok := err.(*ghtml.Error); ok {
+		e.File = fn
+	}
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(strings.TrimSuffix(fn, ".ghtml")+"_ghtml.go", out, 0644)
+}
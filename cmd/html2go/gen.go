@@ -0,0 +1,546 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gohtml/html/internal/tags"
+	xhtml "golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Options controls the generated code.
+type Options struct {
+	// If not empty, the code is wrapped in a function of the name with the
+	// placeholders as its string parameters, in a file of package Package.
+	Func    string
+	Package string
+	// The name of the source, shown in the header of the file.
+	Source string
+}
+
+// placeholderRegexp matches placeholders like {{title}} in texts and
+// attribute values.
+var placeholderRegexp = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// Tags with constructors of children only, e.g. DIV(children ...Node).
+var childrenTags = map[string]bool{
+	"b": true, "button": true, "caption": true, "dd": true, "div": true, "dl": true, "dt": true,
+	"footer": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"li": true, "nav": true, "noscript": true, "object": true, "ol": true, "p": true, "pre": true,
+	"rb": true, "rp": true, "rt": true, "rtc": true, "ruby": true, "small": true, "span": true,
+	"td": true, "th": true, "ul": true, "picture": true,
+}
+
+// Tags with constructors of *Element children only, e.g. TABLE(children ...*Element).
+var elementChildrenTags = map[string]bool{
+	"table": true, "tbody": true, "tfoot": true, "thead": true, "tr": true, "select": true,
+}
+
+// Elements whose texts are kept as they are.
+var rawTextTags = map[string]bool{
+	"pre": true, "textarea": true, "script": true, "style": true,
+}
+
+// Go types of generated expressions.
+const (
+	elementType = "*html.Element"
+	voidType    = "*html.Void"
+	foreignType = "*html.Foreign"
+	textType    = "html.HTMLNode"
+	nodesType   = "[]html.Node"
+)
+
+type expr struct {
+	code string
+	tp   string
+	// Whether the code spans multiple lines.
+	multiline bool
+}
+
+type generator struct {
+	// The names of placeholders in the order of their first appearances.
+	params       []string
+	seen         map[string]bool
+	usesElements bool
+	// The first error, e.g. of an invalid placeholder.
+	err error
+}
+
+// reservedNames are the identifiers used by the generated code, which can't
+// be placeholders.
+var reservedNames = map[string]bool{
+	"_": true, "html": true, "elements": true, "h": true,
+}
+
+func (g *generator) placeholder(name string) string {
+	if (token.IsKeyword(name) || reservedNames[name]) && g.err == nil {
+		g.err = fmt.Errorf("placeholder {{%s}}: %s is reserved", name, name)
+	}
+	if !g.seen[name] {
+		g.seen[name] = true
+		g.params = append(g.params, name)
+	}
+	return name
+}
+
+// str returns the Go expression of s with placeholders replaced by the
+// parameters.
+func (g *generator) str(s string) string {
+	var parts []string
+	last := 0
+	for _, m := range placeholderRegexp.FindAllStringSubmatchIndex(s, -1) {
+		if m[0] > last {
+			parts = append(parts, strconv.Quote(s[last:m[0]]))
+		}
+		parts = append(parts, g.placeholder(s[m[2]:m[3]]))
+		last = m[1]
+	}
+	if last < len(s) || len(parts) == 0 {
+		parts = append(parts, strconv.Quote(s[last:]))
+	}
+	return strings.Join(parts, " + ")
+}
+
+// url is same as str but for URL arguments.
+func (g *generator) url(s string) string {
+	if !placeholderRegexp.MatchString(s) {
+		return strconv.Quote(s)
+	}
+	g.usesElements = true
+	return "elements.URL(" + g.str(s) + ")"
+}
+
+type attr struct {
+	name, value string
+}
+
+type attrList []attr
+
+// take removes the attribute of the name and returns its value.
+func (al *attrList) take(name string) (string, bool) {
+	for i, a := range *al {
+		if a.name == name {
+			*al = append((*al)[:i], (*al)[i+1:]...)
+			return a.value, true
+		}
+	}
+	return "", false
+}
+
+// takeNonEmpty removes the attribute if it is not empty.
+func (al *attrList) takeNonEmpty(name string) string {
+	for _, a := range *al {
+		if a.name == name && a.value != "" {
+			v, _ := al.take(name)
+			return v
+		}
+	}
+	return ""
+}
+
+func (al attrList) has(names ...string) bool {
+	for _, name := range names {
+		found := false
+		for _, a := range al {
+			found = found || a.name == name
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func call(fn string, args []string, children []expr) expr {
+	multiline := len(children) > 1
+	for _, c := range children {
+		multiline = multiline || c.multiline
+	}
+
+	var b strings.Builder
+	b.WriteString(fn + "(" + strings.Join(args, ", "))
+	if multiline {
+		if len(args) > 0 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n")
+		for _, c := range children {
+			b.WriteString(c.code + ",\n")
+		}
+	} else {
+		for i, c := range children {
+			if i > 0 || len(args) > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(c.code)
+		}
+	}
+	b.WriteString(")")
+	return expr{code: b.String(), multiline: multiline}
+}
+
+// text returns the text of n if all children of n are texts.
+func textOf(n *xhtml.Node) (string, bool) {
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		switch c.Type {
+		case xhtml.TextNode:
+			b.WriteString(c.Data)
+		case xhtml.CommentNode:
+		default:
+			return "", false
+		}
+	}
+	return b.String(), true
+}
+
+// children generates the children of n. Whitespace in texts is collapsed and
+// trimmed at both ends of n, unless n or an ancestor, as pre tells, keeps raw
+// texts.
+func (g *generator) children(n *xhtml.Node, pre bool) []expr {
+	raw := pre || rawTextTags[n.Data]
+	var res []expr
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		switch c.Type {
+		case xhtml.TextNode:
+			txt := c.Data
+			if !raw {
+				txt = tags.CollapseSpaces(txt, c.PrevSibling == nil, c.NextSibling == nil)
+				if txt == " " && strings.ContainsAny(c.Data, "\n\r") && (isBlock(c.PrevSibling) || isBlock(c.NextSibling)) {
+					// Line breaks next to blocks are for formatting.
+					txt = ""
+				}
+			}
+			if txt != "" {
+				res = append(res, expr{code: "html.T(" + g.str(txt) + ")", tp: textType})
+			}
+		case xhtml.ElementNode:
+			res = append(res, g.element(c, raw))
+		}
+	}
+	return res
+}
+
+func isBlock(n *xhtml.Node) bool {
+	return n != nil && n.Type == xhtml.ElementNode && n.Namespace == "" && tags.Block[n.Data]
+}
+
+func (g *generator) tagConst(name string) string {
+	g.usesElements = true
+	return "elements." + strings.ToUpper(name) + "Tag"
+}
+
+func allElements(children []expr) bool {
+	for _, c := range children {
+		if c.tp != elementType {
+			return false
+		}
+	}
+	return true
+}
+
+// element generates the code of an element. pre is true in elements keeping
+// raw texts.
+func (g *generator) element(n *xhtml.Node, pre bool) expr {
+	var attrs attrList
+	for _, a := range n.Attr {
+		name := a.Key
+		if a.Namespace != "" {
+			name = a.Namespace + ":" + name
+		}
+		attrs = append(attrs, attr{name: name, value: a.Val})
+	}
+
+	e := g.constructor(n, &attrs, pre)
+	for _, a := range attrs {
+		if a.name == "class" && !placeholderRegexp.MatchString(a.value) {
+			var classes []string
+			for _, cls := range strings.Fields(a.value) {
+				classes = append(classes, strconv.Quote(cls))
+			}
+			e.code += ".AddClass(" + strings.Join(classes, ", ") + ")"
+			continue
+		}
+		e.code += ".Attr(" + strconv.Quote(a.name) + ", " + g.str(a.value) + ")"
+	}
+	return e
+}
+
+// constructor generates the call of the constructor of n. Attributes passed to
+// the constructor are removed from attrs.
+func (g *generator) constructor(n *xhtml.Node, attrs *attrList, pre bool) expr {
+	name := n.Data
+	if _, ok := tags.Types[name]; !ok || n.Namespace != "" {
+		e := call("html.FOREIGN", []string{strconv.Quote(name)}, g.children(n, pre))
+		e.tp = foreignType
+		return e
+	}
+
+	txt, textOnly := textOf(n)
+	switch {
+	case name == "a":
+		e := call("html.A", []string{g.str(attrs.takeNonEmpty("href"))}, g.children(n, pre))
+		e.tp = elementType
+		return e
+
+	case name == "img" && attrs.has("src"):
+		src, _ := attrs.take("src")
+		return expr{code: "html.IMG(" + g.url(src) + ", " + g.str(attrs.takeNonEmpty("alt")) + ")", tp: voidType}
+
+	case name == "input":
+		args := []string{g.str(attrs.takeNonEmpty("type")), g.str(attrs.takeNonEmpty("name")), g.str(attrs.takeNonEmpty("value"))}
+		return expr{code: "html.INPUT(" + strings.Join(args, ", ") + ")", tp: voidType}
+
+	case name == "link" && attrs.has("href", "rel"):
+		href, _ := attrs.take("href")
+		rel, _ := attrs.take("rel")
+		return expr{code: "html.LINK(" + g.url(href) + ", " + g.str(rel) + ")", tp: voidType}
+
+	case name == "base":
+		return expr{code: "html.BASE(" + g.url(attrs.takeNonEmpty("href")) + ", " + g.str(attrs.takeNonEmpty("target")) + ")", tp: voidType}
+
+	case name == "source" && attrs.has("srcset"):
+		srcset, _ := attrs.take("srcset")
+		return expr{code: "html.SOURCE(" + g.str(srcset) + ", " + g.str(attrs.takeNonEmpty("type")) + ")", tp: voidType}
+
+	case name == "param" && attrs.has("name", "value"):
+		pn, _ := attrs.take("name")
+		pv, _ := attrs.take("value")
+		return expr{code: "html.PARAM(" + g.str(pn) + ", " + g.str(pv) + ")", tp: voidType}
+
+	case name == "br" || name == "hr" || name == "meta":
+		return expr{code: "html." + strings.ToUpper(name) + "()", tp: voidType}
+
+	case tags.Void[name]:
+		return expr{code: "html.NewVoid(" + g.tagConst(name) + ")", tp: voidType}
+
+	case name == "label":
+		e := call("html.LABEL", []string{g.str(attrs.takeNonEmpty("for"))}, g.children(n, pre))
+		e.tp = elementType
+		return e
+
+	case name == "map":
+		e := call("html.MAP", []string{g.str(attrs.takeNonEmpty("name"))}, g.children(n, pre))
+		e.tp = elementType
+		return e
+
+	case name == "form" && attrs.has("method", "action"):
+		method, _ := attrs.take("method")
+		action, _ := attrs.take("action")
+		e := call("html.FORM", []string{g.str(method), g.str(action)}, g.children(n, pre))
+		e.tp = elementType
+		return e
+
+	case name == "title" && textOnly:
+		return expr{code: "html.TITLE(" + g.str(tags.CollapseSpaces(txt, true, true)) + ")", tp: elementType}
+
+	case name == "option" && textOnly && attrs.has("value"):
+		value, _ := attrs.take("value")
+		return expr{code: "html.OPTION(" + g.str(value) + ", " + g.str(tags.CollapseSpaces(txt, true, true)) + ")", tp: elementType}
+
+	case (name == "script" || name == "style") && textOnly:
+		if name == "style" {
+			return expr{code: "html.STYLE(" + strconv.Quote(txt) + ")", tp: elementType}
+		}
+		return expr{code: "html.SCRIPT(" + g.url(attrs.takeNonEmpty("src")) + ", " + strconv.Quote(txt) + ")", tp: elementType}
+
+	case childrenTags[name]:
+		e := call("html."+strings.ToUpper(name), nil, g.children(n, pre))
+		e.tp = elementType
+		return e
+
+	case elementChildrenTags[name]:
+		children := g.children(n, pre)
+		if allElements(children) {
+			e := call("html."+strings.ToUpper(name), nil, children)
+			e.tp = elementType
+			return e
+		}
+	}
+
+	e := call("html.NewElement", []string{g.tagConst(name)}, g.children(n, pre))
+	e.tp = elementType
+	return e
+}
+
+// document generates the statements building an Html.
+func (g *generator) document(doc *xhtml.Node) []string {
+	root := doc.FirstChild
+	for root != nil && (root.Type != xhtml.ElementNode || root.DataAtom != atom.Html) {
+		root = root.NextSibling
+	}
+	if root == nil {
+		return nil
+	}
+
+	var attrs attrList
+	for _, a := range root.Attr {
+		attrs = append(attrs, attr{name: a.Key, value: a.Val})
+	}
+	stmts := []string{"h := html.HTML(" + g.str(attrs.takeNonEmpty("lang")) + ")"}
+	for _, a := range attrs {
+		stmts = append(stmts, "h.Attr("+strconv.Quote(a.name)+", "+g.str(a.value)+")")
+	}
+
+	for n := root.FirstChild; n != nil; n = n.NextSibling {
+		if n.Type != xhtml.ElementNode {
+			continue
+		}
+		switch n.DataAtom {
+		case atom.Head:
+			stmts = append(stmts, g.head(n)...)
+		case atom.Body:
+			body := "h.Body()"
+			for _, a := range n.Attr {
+				stmts = append(stmts, body+".Attr("+strconv.Quote(a.Key)+", "+g.str(a.Val)+")")
+			}
+			if children := g.children(n, false); len(children) > 0 {
+				e := call(body+".Child", nil, children)
+				stmts = append(stmts, e.code)
+			}
+		}
+	}
+	return stmts
+}
+
+// head generates the statements for the children of the HEAD. The charset META
+// is omitted since HTML sets it.
+func (g *generator) head(n *xhtml.Node) []string {
+	var (
+		stmts  []string
+		others []expr
+	)
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != xhtml.ElementNode {
+			continue
+		}
+		attrs := attrList{}
+		for _, a := range c.Attr {
+			attrs = append(attrs, attr{name: a.Key, value: a.Val})
+		}
+		txt, textOnly := textOf(c)
+		switch {
+		case c.DataAtom == atom.Meta && len(attrs) == 1 && attrs.has("charset"):
+		case c.DataAtom == atom.Title && textOnly:
+			stmts = append(stmts, "h.Title("+g.str(tags.CollapseSpaces(txt, true, true))+")")
+		case c.DataAtom == atom.Link && len(attrs) == 2 && attrs.has("href", "rel"):
+			href, _ := attrs.take("href")
+			rel, _ := attrs.take("rel")
+			if rel == "stylesheet" {
+				stmts = append(stmts, "h.Css("+g.url(href)+")")
+			} else {
+				stmts = append(stmts, "h.HeadSet().Link("+g.url(href)+", "+g.str(rel)+")")
+			}
+		case c.DataAtom == atom.Meta && len(attrs) == 2 && attrs.has("name", "content"):
+			name, _ := attrs.take("name")
+			content, _ := attrs.take("content")
+			stmts = append(stmts, "h.HeadSet().Meta("+g.str(name)+", "+g.str(content)+")")
+		default:
+			others = append(others, g.element(c, false))
+		}
+	}
+	if len(others) > 0 {
+		stmts = append(stmts, call("h.Head().Child", nil, others).code)
+	}
+	return stmts
+}
+
+// isDocument returns true if src is a whole document rather than a fragment.
+func isDocument(src []byte) bool {
+	lower := bytes.ToLower(src)
+	return bytes.Contains(lower, []byte("<!doctype")) || bytes.Contains(lower, []byte("<html"))
+}
+
+// Generate converts the HTML read from r into Go code building it with
+// package html.
+func Generate(r io.Reader, opts Options) ([]byte, error) {
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	g := &generator{seen: make(map[string]bool)}
+
+	var (
+		body   []string
+		result expr
+	)
+	if isDocument(src) {
+		doc, err := xhtml.Parse(bytes.NewReader(src))
+		if err != nil {
+			return nil, err
+		}
+		body = g.document(doc)
+		result = expr{code: "h", tp: "*html.Html"}
+	} else {
+		nodes, err := xhtml.ParseFragment(bytes.NewReader(src), &xhtml.Node{
+			Type:     xhtml.ElementNode,
+			Data:     "body",
+			DataAtom: atom.Body,
+		})
+		if err != nil {
+			return nil, err
+		}
+		parent := &xhtml.Node{Type: xhtml.ElementNode, Data: "div", DataAtom: atom.Div}
+		for _, n := range nodes {
+			parent.AppendChild(n)
+		}
+		exprs := g.children(parent, false)
+		switch len(exprs) {
+		case 0:
+			return nil, fmt.Errorf("no element or text found")
+		case 1:
+			result = exprs[0]
+		default:
+			result = call(nodesType, nil, exprs)
+			result.code = strings.Replace(result.code, nodesType+"(", nodesType+"{", 1)
+			result.code = result.code[:len(result.code)-1] + "}"
+			result.tp = nodesType
+		}
+	}
+
+	if g.err != nil {
+		return nil, g.err
+	}
+
+	var b bytes.Buffer
+	if opts.Func == "" {
+		for _, stmt := range body {
+			b.WriteString(stmt + "\n")
+		}
+		if len(body) == 0 {
+			b.WriteString(result.code + "\n")
+		}
+	} else {
+		if opts.Source != "" {
+			fmt.Fprintf(&b, "// Code generated by html2go from %s.\n\n", opts.Source)
+		}
+		fmt.Fprintf(&b, "package %s\n\nimport (\n", opts.Package)
+		if g.usesElements {
+			b.WriteString("\t\"github.com/gohtml/elements\"\n")
+		}
+		b.WriteString("\t\"github.com/gohtml/html\"\n)\n\n")
+
+		var params string
+		if len(g.params) > 0 {
+			params = strings.Join(g.params, ", ") + " string"
+		}
+		fmt.Fprintf(&b, "func %s(%s) %s {\n", opts.Func, params, result.tp)
+		for _, stmt := range body {
+			b.WriteString(stmt + "\n")
+		}
+		b.WriteString("return " + result.code + "\n}\n")
+	}
+
+	out, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %v", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+
+	"github.com/golangplus/testing/assert"
+)
+
+func generate(t *testing.T, src string, opts Options) string {
+	out, err := Generate(strings.NewReader(src), opts)
+	assert.NoError(t, err)
+	return string(out)
+}
+
+func TestGenerate_fragment(t *testing.T) {
+	src := `
+<nav class="menu main" id="nav">
+  <ul>
+    <li class="active"><a href="/">Home</a></li>
+    <li><a href="/about">About <b>us</b></a></li>
+  </ul>
+</nav>
+<img src="logo.png" alt="">
+`
+	assert.Equal(t, "code", generate(t, src, Options{}), `[]html.Node{
+	html.NAV(
+		html.UL(
+			html.LI(html.A("/", html.T("Home"))).AddClass("active"),
+			html.LI(
+				html.A("/about",
+					html.T("About "),
+					html.B(html.T("us")),
+				),
+			),
+		),
+	).AddClass("menu", "main").Attr("id", "nav"),
+	html.IMG("logo.png", "").Attr("alt", ""),
+}
+`)
+}
+
+// typeCheck type-checks the generated file with the packages imported from
+// their sources.
+func typeCheck(t *testing.T, name, src string) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, name, src, 0)
+	assert.NoError(t, err)
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	_, err = conf.Check("views", fset, []*ast.File{f}, nil)
+	assert.NoError(t, err)
+}
+
+func TestGenerate_spaces(t *testing.T) {
+	src := "<p>\n  <b>a</b>\n  <em>b</em>\n</p>\n<div>\n  <p>c</p>\n  <p>d</p>\n</div>"
+	assert.Equal(t, "code", generate(t, src, Options{}), `[]html.Node{
+	html.P(
+		html.B(html.T("a")),
+		html.T(" "),
+		html.NewElement(elements.EMTag, html.T("b")),
+	),
+	html.DIV(
+		html.P(html.T("c")),
+		html.P(html.T("d")),
+	),
+}
+`)
+}
+
+func TestGenerate_reserved(t *testing.T) {
+	for _, name := range []string{"type", "html", "elements", "h", "_"} {
+		_, err := Generate(strings.NewReader("<p>{{"+name+"}}</p>"), Options{Func: "F", Package: "views"})
+		assert.Error(t, err)
+	}
+}
+
+func TestGenerate_constructors(t *testing.T) {
+	for _, c := range []struct {
+		src, exp string
+	}{
+		{`<input type="text" name="q" placeholder="Search">`, `html.INPUT("text", "q", "").Attr("placeholder", "Search")`},
+		{`<table><tr><td>1</td></tr></table>`, `html.TABLE(html.TBODY(html.TR(html.TD(html.T("1")))))`},
+		{`<article><h2>T</h2></article>`, `html.NewElement(elements.ARTICLETag, html.H2(html.T("T")))`},
+		{`<p>a<wbr>b</p>`, "html.P(\n\thtml.T(\"a\"),\n\thtml.NewVoid(elements.WBRTag),\n\thtml.T(\"b\"),\n)"},
+		{`<select name="c"><option value="1">One</option></select>`, `html.SELECT(html.OPTION("1", "One")).Attr("name", "c")`},
+		{`<form method="post" action="/login"><label for="u">User</label></form>`, `html.FORM("post", "/login", html.LABEL("u", html.T("User")))`},
+		{`<form><button>Go</button></form>`, `html.NewElement(elements.FORMTag, html.BUTTON(html.T("Go")))`},
+		{`<my-widget data-x="1"></my-widget>`, `html.FOREIGN("my-widget").Attr("data-x", "1")`},
+		{`<svg viewBox="0 0 1 1"><circle r="1"/></svg>`, `html.FOREIGN("svg", html.FOREIGN("circle").Attr("r", "1")).Attr("viewBox", "0 0 1 1")`},
+		{"<pre>a\n  b</pre>", `html.PRE(html.T("a\n  b"))`},
+		{"<pre><code>a\n  b</code></pre>", `html.PRE(html.NewElement(elements.CODETag, html.T("a\n  b")))`},
+		{`<!-- comment --><br>`, `html.BR()`},
+	} {
+		assert.Equal(t, c.src, generate(t, c.src, Options{}), c.exp+"\n")
+	}
+}
+
+func TestGenerate_func(t *testing.T) {
+	src := `<a class="card {{kind}}" href="{{url}}"><img src="{{image}}" alt="{{title}}">{{title}} ({{count}})</a>`
+	out := generate(t, src, Options{Func: "Card", Package: "views", Source: "card.html"})
+	assert.Equal(t, "code", out, `// Code generated by html2go from card.html.
+
+package views
+
+import (
+	"github.com/gohtml/elements"
+	"github.com/gohtml/html"
+)
+
+func Card(url, image, title, count, kind string) *html.Element {
+	return html.A(url,
+		html.IMG(elements.URL(image), title),
+		html.T(title+" ("+count+")"),
+	).Attr("class", "card "+kind)
+}
+`)
+	typeCheck(t, "card.go", out)
+}
+
+func TestGenerate_document(t *testing.T) {
+	src := `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Home</title>
+  <link rel="stylesheet" href="main.css">
+  <meta name="description" content="{{desc}}">
+  <script src="app.js"></script>
+</head>
+<body class="home">
+  <h1>Welcome</h1>
+</body>
+</html>`
+	out := generate(t, src, Options{Func: "Page", Package: "main"})
+	assert.Equal(t, "code", out, `package main
+
+import (
+	"github.com/gohtml/html"
+)
+
+func Page(desc string) *html.Html {
+	h := html.HTML("en")
+	h.Title("Home")
+	h.Css("main.css")
+	h.HeadSet().Meta("description", desc)
+	h.Head().Child(html.SCRIPT("app.js", ""))
+	h.Body().Attr("class", "home")
+	h.Body().Child(html.H1(html.T("Welcome")))
+	return h
+}
+`)
+	typeCheck(t, "page.go", out)
+}
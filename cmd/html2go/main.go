@@ -0,0 +1,66 @@
+// Command html2go converts HTML files into Go code building the same trees
+// with package github.com/gohtml/html.
+//
+// Usage:
+//
+//	html2go [-func Name [-pkg name]] [file.html]
+//
+// The HTML is read from the standard input if no file is given. Placeholders
+// like {{title}} in texts and attribute values become Go expressions. With
+// -func, the code is wrapped in a function of the name, in a Go file, with the
+// placeholders as its string parameters:
+//
+//	<a class="card" href="{{url}}">{{title}}</a>
+//
+// is converted by "html2go -func Card" into
+//
+//	func Card(url, title string) *html.Element {
+//		return html.A(url, html.T(title)).AddClass("card")
+//	}
+//
+// Placeholders can't be Go keywords or the names used by the generated code:
+// _, html, elements and h.
+//
+// Whitespace in texts is collapsed except in PRE, TEXTAREA, SCRIPT and STYLE.
+// Whitespace-only texts with line breaks next to block-level elements are
+// dropped. Comments are dropped.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	var opts Options
+	flag.StringVar(&opts.Func, "func", "", "wrap the code in a function of the `name`")
+	flag.StringVar(&opts.Package, "pkg", "main", "the package of the generated file, used with -func")
+	flag.Parse()
+
+	var in io.Reader = os.Stdin
+	switch flag.NArg() {
+	case 0:
+	case 1:
+		f, err := os.Open(flag.Arg(0))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		in = f
+		opts.Source = filepath.Base(flag.Arg(0))
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	out, err := Generate(in, opts)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "html2go:", err)
+		os.Exit(1)
+	}
+	os.Stdout.Write(out)
+}
@@ -0,0 +1,185 @@
+package html
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	stdhtml "html"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// CSRFToken is a per-request token injected into FORMs at rendering if set to
+// RenderOptions.CSRF. A hidden INPUT containing the token is injected into
+// every FORM whose method is POST, and whose action is relative or of the same
+// origin.
+//
+// Browsers submit FORMs with other methods, e.g. PUT or DELETE, as GET, so the
+// token is not injected into them. Send such requests with fetch instead, and
+// put the token in the header checked by CSRF:
+//
+//	fetch(url, {method: "DELETE", headers: {"X-CSRF-Token": token}})
+//
+// Call CSRFTokenOf in handlers wrapped by CSRF.Handler to get the token.
+type CSRFToken struct {
+	// The name of the hidden INPUT.
+	Name  string
+	Token string
+	// The origin of the site, e.g. "https://example.com". If empty, only FORMs
+	// with relative actions are injected.
+	Origin string
+}
+
+// injects returns true if the token should be injected into the FORM.
+func (t *CSRFToken) injects(form *Element) bool {
+	if method, _ := form.attributes.get("method"); !strings.EqualFold(string(method), "post") {
+		return false
+	}
+
+	action, _ := form.attributes.get("action")
+	s := normalizeURL(stdhtml.UnescapeString(string(action)))
+	if strings.HasPrefix(s, "//") {
+		// Browsers treat any number of slashes as the start of the authority.
+		s = "//" + strings.TrimLeft(s, "/")
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return false
+	}
+	if t.Origin == "" {
+		return u.Scheme == "" && u.Host == ""
+	}
+
+	// Resolve the action against the origin and compare the origins.
+	base, err := url.Parse(t.Origin + "/")
+	if err != nil {
+		return false
+	}
+	r := base.ResolveReference(u)
+	return strings.EqualFold(r.Scheme, base.Scheme) && strings.EqualFold(r.Host, base.Host)
+}
+
+// normalizeURL removes and replaces characters the way the URL standard does
+// before parsing, so that the URL is parsed as browsers do: leading and
+// trailing C0 controls and spaces are trimmed, tabs and newlines are removed,
+// and backslashes are treated as slashes.
+func normalizeURL(s string) string {
+	s = strings.TrimFunc(s, func(r rune) bool {
+		return r <= ' '
+	})
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '\t', '\n', '\r':
+			return -1
+		case '\\':
+			return '/'
+		}
+		return r
+	}, s)
+}
+
+func (t *CSRFToken) writeTo(b Writer, opt RenderOptions, form *Element) {
+	INPUT("hidden", t.Name, t.Token).WriteTo(b, opt, form, 0)
+}
+
+// CSRF is a middleware protecting handlers against cross-site request forgery.
+//
+// A random secret is kept in a cookie, and the token is an HMAC of it. Requests
+// with methods other than GET, HEAD, OPTIONS and TRACE must submit the token in
+// the form field or the header, otherwise they are rejected.
+type CSRF struct {
+	// The key to sign tokens. Required.
+	Key []byte
+	// The origin of the site, e.g. "https://example.com". See CSRFToken.Origin.
+	Origin string
+	// The name of the form field. Default is "csrf_token".
+	FieldName string
+	// The name of the header, used by scripts. Default is "X-CSRF-Token".
+	HeaderName string
+	// The name of the cookie. Default is "csrf".
+	CookieName string
+	// Called when a request is rejected. Default responds 403 Forbidden.
+	ErrorHandler http.Handler
+}
+
+type csrfContextKey struct{}
+
+// CSRFTokenOf returns the CSRFToken of a request handled by CSRF.Handler, or
+// nil if there is none.
+func CSRFTokenOf(r *http.Request) *CSRFToken {
+	t, _ := r.Context().Value(csrfContextKey{}).(*CSRFToken)
+	return t
+}
+
+func stringOrDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
+
+func (c *CSRF) token(secret string) string {
+	mac := hmac.New(sha256.New, c.Key)
+	mac.Write([]byte(secret))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Handler returns a handler verifying requests before calling next.
+func (c *CSRF) Handler(next http.Handler) http.Handler {
+	if len(c.Key) == 0 {
+		panic("CSRF: Key is required")
+	}
+	fieldName := stringOrDefault(c.FieldName, "csrf_token")
+	headerName := stringOrDefault(c.HeaderName, "X-CSRF-Token")
+	cookieName := stringOrDefault(c.CookieName, "csrf")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var secret string
+		if ck, err := r.Cookie(cookieName); err == nil && ck.Value != "" {
+			secret = ck.Value
+		} else {
+			var rnd [32]byte
+			if _, err := rand.Read(rnd[:]); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			secret = base64.RawURLEncoding.EncodeToString(rnd[:])
+			http.SetCookie(w, &http.Cookie{
+				Name:     cookieName,
+				Value:    secret,
+				Path:     "/",
+				HttpOnly: true,
+				Secure:   r.TLS != nil,
+				SameSite: http.SameSiteLaxMode,
+			})
+		}
+		token := c.token(secret)
+
+		switch r.Method {
+		case "GET", "HEAD", "OPTIONS", "TRACE":
+		default:
+			got := r.Header.Get(headerName)
+			if got == "" {
+				got = r.PostFormValue(fieldName)
+			}
+			if !hmac.Equal([]byte(got), []byte(token)) {
+				if c.ErrorHandler != nil {
+					c.ErrorHandler.ServeHTTP(w, r)
+				} else {
+					http.Error(w, "invalid CSRF token", http.StatusForbidden)
+				}
+				return
+			}
+		}
+
+		ctx := context.WithValue(r.Context(), csrfContextKey{}, &CSRFToken{
+			Name:   fieldName,
+			Token:  token,
+			Origin: c.Origin,
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,99 @@
+package html
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/golangplus/testing/assert"
+)
+
+func TestCSRFToken_inject(t *testing.T) {
+	opt := RenderOptions{SortAttr: true, CSRF: &CSRFToken{
+		Name:   "csrf_token",
+		Token:  "TOKEN",
+		Origin: "https://example.com",
+	}}
+	input := `<input name="csrf_token" type="hidden" value="TOKEN">`
+
+	for _, c := range []struct {
+		method, action string
+		injected       bool
+	}{
+		{"POST", "/save", true},
+		{"post", "", true},
+		{"Post", "https://example.com/item?a=1&b=2", true},
+		{"DELETE", "/item", false},
+		{"put", "/item", false},
+		{"GET", "/search", false},
+		{"POST", "https://evil.com/save", false},
+		{"POST", "//evil.com/save", false},
+		{"POST", `/\evil.com/x`, false},
+		{"POST", ` //evil.com/x`, false},
+		{"POST", "\t/\n/evil.com/x", false},
+		{"POST", "///evil.com/x", false},
+		{"POST", `\\evil.com\x`, false},
+		{"POST", "HTTPS://EXAMPLE.COM/x", true},
+		{"POST", " save ", true},
+	} {
+		form := NodeToHTMLNode(FORM(c.method, c.action), opt)
+		assert.Equal(t, c.method+" "+c.action, strings.Contains(string(form), input), c.injected)
+	}
+}
+
+func TestCSRF_Handler(t *testing.T) {
+	csrf := &CSRF{Key: []byte("secret")}
+	h := csrf.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		opt := DefaultOptions
+		opt.CSRF = CSRFTokenOf(r)
+		w.Write([]byte(NodeToHTMLNode(FORM("POST", "/"), opt)))
+	}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, "code", w.Code, http.StatusOK)
+	cookies := w.Result().Cookies()
+	assert.Equal(t, "len(cookies)", len(cookies), 1)
+
+	token := CSRFTokenOf(httptest.NewRequest("GET", "/", nil))
+	assert.True(t, "token == nil", token == nil)
+
+	body := w.Body.String()
+	const prefix = `value="`
+	p := strings.Index(body, prefix)
+	assert.ValueShould(t, "body", body, p >= 0, "contains no token")
+	tk := body[p+len(prefix):]
+	tk = tk[:strings.IndexByte(tk, '"')]
+
+	post := func(tk string) int {
+		r := httptest.NewRequest("POST", "/", strings.NewReader(url.Values{"csrf_token": {tk}}.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		r.AddCookie(cookies[0])
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Code
+	}
+	assert.Equal(t, "valid", post(tk), http.StatusOK)
+	assert.Equal(t, "invalid", post("wrong"), http.StatusForbidden)
+	assert.Equal(t, "missing", post(""), http.StatusForbidden)
+}
+
+func TestCSRFToken_inject_noOrigin(t *testing.T) {
+	opt := RenderOptions{CSRF: &CSRFToken{Name: "csrf", Token: "SECRET"}}
+	for _, c := range []struct {
+		action   string
+		injected bool
+	}{
+		{"/save", true},
+		{"save?a=1", true},
+		{`/\evil.com/x`, false},
+		{` //evil.com/x`, false},
+		{"///evil.com/x", false},
+		{"https://example.com/x", false},
+	} {
+		form := NodeToHTMLNode(FORM("POST", c.action), opt)
+		assert.Equal(t, c.action, strings.Contains(string(form), "SECRET"), c.injected)
+	}
+}
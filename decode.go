@@ -0,0 +1,144 @@
+package html
+
+import (
+	"fmt"
+	"math"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"unicode/utf8"
+)
+
+// setField parses s and sets it to v. A non-nil error message is returned if s
+// cannot be parsed.
+func setField(v reflect.Value, s string) string {
+	switch v.Kind() {
+	case reflect.Bool:
+		v.SetBool(s != "" && s != "false")
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return "Please enter a whole number."
+		}
+		v.SetInt(n)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return "Please enter a whole number."
+		}
+		v.SetUint(n)
+
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		// NaN and infinities are not numbers to users and escape min and max.
+		if err != nil || math.IsNaN(n) || math.IsInf(n, 0) {
+			return "Please enter a number."
+		}
+		v.SetFloat(n)
+
+	case reflect.String:
+		v.SetString(s)
+	}
+	return ""
+}
+
+// validate checks the value s of the field with the rules in its tag. An error
+// message is returned if any rule is violated.
+func (f *formField) validate(v reflect.Value, s string) string {
+	switch f.tp {
+	case "select":
+		for _, c := range v.Interface().(Chooser).Choices() {
+			if c.Value == s {
+				return ""
+			}
+		}
+		return "Please select a valid option."
+
+	case "number":
+		n, err := strconv.ParseFloat(s, 64)
+		if err != nil || math.IsNaN(n) || math.IsInf(n, 0) {
+			return "Please enter a number."
+		}
+		if min, err := strconv.ParseFloat(f.min, 64); err == nil && n < min {
+			return fmt.Sprintf("Must be at least %s.", f.min)
+		}
+		if max, err := strconv.ParseFloat(f.max, 64); err == nil && n > max {
+			return fmt.Sprintf("Must be at most %s.", f.max)
+		}
+		return ""
+
+	case "email":
+		if _, err := mail.ParseAddress(s); err != nil {
+			return "Please enter an email address."
+		}
+
+	case "url":
+		if u, err := url.Parse(s); err != nil || u.Scheme == "" {
+			return "Please enter a URL."
+		}
+	}
+
+	l := utf8.RuneCountInString(s)
+	if min, err := strconv.Atoi(f.minLength); err == nil && l < min {
+		return fmt.Sprintf("Must be at least %d characters.", min)
+	}
+	if max, err := strconv.Atoi(f.maxLength); err == nil && l > max {
+		return fmt.Sprintf("Must be at most %d characters.", max)
+	}
+	if f.pattern != "" {
+		// Patterns of INPUTs must match the whole value.
+		re, err := regexp.Compile(`^(?:` + f.pattern + `)$`)
+		if err == nil && !re.MatchString(s) {
+			return "Please match the requested format."
+		}
+	}
+	return ""
+}
+
+// DecodeForm decodes submitted form values into v, which must be a pointer to
+// a struct. Fields are decoded and validated with the rules in their html tags
+// as FormFor renders them, e.g. required, min, max, minlength, maxlength,
+// pattern and the options of Choosers.
+//
+// The returned FormErrors contains the error messages of invalid fields and
+// can be set to FormOptions.Errors to render the form again. It is empty if
+// all fields are valid. Invalid fields of v are left unchanged.
+func DecodeForm(vals url.Values, v interface{}) FormErrors {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		panic("DecodeForm: v should be a pointer to a struct")
+	}
+	rv = rv.Elem()
+
+	errs := make(FormErrors)
+	// Invalid fields are skipped as FormFor does, which reports the error.
+	fields, _ := formFields(rv.Type())
+	for _, f := range fields {
+		fv := rv.Field(f.index)
+		s := vals.Get(f.name)
+		if s == "" {
+			if f.required {
+				errs[f.name] = "This field is required."
+				continue
+			}
+			fv.Set(reflect.Zero(fv.Type()))
+			continue
+		}
+
+		nv := reflect.New(fv.Type()).Elem()
+		if msg := setField(nv, s); msg != "" {
+			errs[f.name] = msg
+			continue
+		}
+		if msg := f.validate(nv, s); msg != "" {
+			errs[f.name] = msg
+			continue
+		}
+		fv.Set(nv)
+	}
+	return errs
+}
@@ -0,0 +1,79 @@
+package html
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/golangplus/testing/assert"
+)
+
+func TestDecodeForm(t *testing.T) {
+	var s signup
+	errs := DecodeForm(url.Values{
+		"email":    {"a@example.com"},
+		"password": {"12345678"},
+		"age":      {"20"},
+		"height":   {"1.8"},
+		"color":    {"2"},
+		"bio":      {"Hi"},
+		"code":     {"ab"},
+		"agree":    {"true"},
+	}, &s)
+	assert.Equal(t, "errs", errs, FormErrors{})
+	assert.Equal(t, "s", s, signup{
+		Email:    "a@example.com",
+		Password: "12345678",
+		Age:      20,
+		Height:   1.8,
+		Color:    2,
+		Bio:      "Hi",
+		Code:     "ab",
+		Agree:    true,
+	})
+}
+
+func TestDecodeForm_errors(t *testing.T) {
+	s := signup{Age: 30}
+	errs := DecodeForm(url.Values{
+		"email":    {"not an email"},
+		"password": {"short"},
+		"age":      {"17"},
+		"height":   {"tall"},
+		"color":    {"5"},
+		"code":     {"abcde"},
+	}, &s)
+	assert.Equal(t, "errs", errs, FormErrors{
+		"email":    "Please enter an email address.",
+		"password": "Must be at least 8 characters.",
+		"age":      "Must be at least 18.",
+		"height":   "Please enter a number.",
+		"color":    "Please select a valid option.",
+		"code":     "Please match the requested format.",
+		"agree":    "This field is required.",
+	})
+	// Invalid fields are unchanged.
+	assert.Equal(t, "s.Age", s.Age, 30)
+
+	// The errors can be rendered by FormFor.
+	form := FormFor(&s, FormOptions{Errors: errs})
+	html := string(NodeToHTMLNode(form, DefaultOptions))
+	assert.ValueShould(t, "form", html, strings.Contains(html, `<span class="error" id="age-error">Must be at least 18.</span>`),
+		"does not contain the error of age")
+
+	assert.Panic(t, "DecodeForm(struct)", func() {
+		DecodeForm(nil, s)
+	})
+}
+
+func TestDecodeForm_nonFinite(t *testing.T) {
+	type ratio struct {
+		Value float64 `html:"value,min=0,max=1"`
+	}
+	for _, v := range []string{"NaN", "Inf", "-Inf", "+Infinity"} {
+		r := ratio{Value: 0.5}
+		errs := DecodeForm(url.Values{"value": {v}}, &r)
+		assert.Equal(t, "errs of "+v, errs, FormErrors{"value": "Please enter a number."})
+		assert.Equal(t, "r.Value", r.Value, 0.5)
+	}
+}
@@ -0,0 +1,150 @@
+package html
+
+import (
+	"fmt"
+	"strings"
+
+	. "github.com/gohtml/elements"
+	"github.com/gohtml/utils"
+)
+
+// ForeignType is the TagType of Foreign elements. It is different from the
+// TagTypes of all HTML elements.
+var ForeignType = TagType(len(TagNames))
+
+// Foreign is an element of foreign content, e.g. SVG or MathML, embedded in
+// HTML. Unlike Void and Element, names of the element and its attributes are
+// case-sensitive, e.g. "viewBox", and an element without children is
+// self-closed.
+//
+// Omitting is never applied to HTML elements inside a Foreign.
+type Foreign struct {
+	name       string
+	attributes Attributes
+	classes    htmlNodeSet
+	children   []Node
+}
+
+var _ Node = (*Foreign)(nil)
+
+// FOREIGN creates a Foreign element. See packages svg and mathml for the
+// constructors of common elements. An invalid name is reported when the element
+// is rendered.
+func FOREIGN(name string, children ...Node) *Foreign {
+	return &Foreign{
+		name:     name,
+		children: children,
+	}
+}
+
+// validForeignName returns false if name contains characters that are not
+// allowed in element or attribute names.
+func validForeignName(name string) bool {
+	if name == "" {
+		return false
+	}
+	return strings.IndexFunc(name, func(r rune) bool {
+		return r <= ' ' || r == '"' || r == '\'' || r == '>' || r == '/' || r == '=' || r == '<' || r == '&'
+	}) < 0
+}
+
+// Implementation of Node interface
+func (f *Foreign) Type() TagType {
+	return ForeignType
+}
+
+// Name returns the case-sensitive name of the element.
+func (f *Foreign) Name() HTMLNode {
+	return HTMLNode(f.name)
+}
+
+// Children returns the children of the element.
+func (f *Foreign) Children() []Node {
+	return f.children
+}
+
+// Attr sets an attribute. The case of the name is preserved. Invalid names are
+// ignored.
+func (f *Foreign) Attr(name, value string) *Foreign {
+	if !validForeignName(name) {
+		return f
+	}
+	if name == "class" {
+		f.classes = f.classes[:0]
+		return f.AddClass(strings.Fields(value)...)
+	}
+	f.attributes.Put(HTMLNode(name), HTMLNode(utils.EscapeAttr(value)))
+	return f
+}
+
+// GetAttr returns the unescaped value of an attribute. The value of "class"
+// is the list of classes separated by spaces.
+func (f *Foreign) GetAttr(name string) (string, bool) {
+	return getAttr(f.attributes, f.classes, name)
+}
+
+// EachAttr calls fn with the name and the unescaped value of every attribute,
+// starting with the class.
+func (f *Foreign) EachAttr(fn func(name, value string)) {
+	eachAttr(f.attributes, f.classes, fn)
+}
+
+// NonEmptyAttr sets the attribute if value is not empty.
+func (f *Foreign) NonEmptyAttr(name, value string) *Foreign {
+	if value == "" {
+		return f
+	}
+	return f.Attr(name, value)
+}
+
+// ID sets the "id" attribute of the element.
+func (f *Foreign) ID(id string) *Foreign {
+	return f.Attr("id", id)
+}
+
+// AddClass adds a class into the class list of the element.
+func (f *Foreign) AddClass(classes ...string) *Foreign {
+	for _, cls := range classes {
+		f.classes.Put(HTMLNode(utils.EscapeAttr(cls)))
+	}
+	return f
+}
+
+// Child appends children of the element.
+func (f *Foreign) Child(children ...Node) *Foreign {
+	f.children = append(f.children, children...)
+	return f
+}
+
+// T appends a text as a child of the element.
+func (f *Foreign) T(txt string) *Foreign {
+	return f.Child(T(txt))
+}
+
+// Implementation of Node interface
+func (f *Foreign) WriteTo(b Writer, opt RenderOptions, parent *Element, childIndex int) {
+	if !validForeignName(f.name) {
+		opt.fail(fmt.Errorf("FOREIGN: invalid element name %q", f.name))
+		return
+	}
+	b.WriteByte('<')
+	b.WriteString(f.name)
+	writeAttrs(b, opt, f.attributes, f.classes)
+
+	if len(f.children) == 0 {
+		b.WriteString("/>")
+		return
+	}
+	b.WriteByte('>')
+
+	// Omitting depends on the parent Element, which is unavailable here.
+	copt := opt
+	copt.DisableOmit = true
+	for i, child := range f.children {
+		child.WriteTo(b, copt, nil, i)
+	}
+
+	b.WriteString("</")
+	b.WriteString(f.name)
+	b.WriteByte('>')
+}
@@ -0,0 +1,37 @@
+package html
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/golangplus/testing/assert"
+)
+
+func TestForeign(t *testing.T) {
+	f := FOREIGN("svg",
+		FOREIGN("foreignObject", P(T("a")), P(T("b"))),
+		FOREIGN("path").Attr("d", `M0 0"`).Attr("bad name", "x"),
+	).Attr("viewBox", "0 0 10 10").AddClass("icon")
+
+	assert.StringEqual(t, "svg", NodeToHTMLNode(f, RenderOptions{SortAttr: true}),
+		`<svg class="icon" viewBox="0 0 10 10"><foreignObject><p>a</p><p>b</p></foreignObject><path d="M0 0&quot;"/></svg>`)
+}
+
+func TestForeign_invalidName(t *testing.T) {
+	for _, name := range []string{"", "a b", "a>b", "<a", "a/"} {
+		var b bytes.Buffer
+		err := Render(&b, DIV(FOREIGN(name, T("x"))), DefaultOptions)
+		assert.Equal(t, "err", fmt.Sprint(err), fmt.Sprintf("FOREIGN: invalid element name %q", name))
+		assert.Equal(t, "b.Len()", b.Len(), 0)
+	}
+}
+
+func TestForeign_inHTML(t *testing.T) {
+	h := HTML("")
+	h.Body().Child(P(T("x")), FOREIGN("svg"), UL(LI(FOREIGN("svg"))))
+
+	assert.StringEqual(t, "html", NodeToHTMLNode(h, RenderOptions{SortAttr: true}),
+		`<!DOCTYPE html>
+<meta charset="utf-8"><p>x</p><svg/><ul><li><svg/></ul>`)
+}
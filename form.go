@@ -0,0 +1,290 @@
+package html
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	. "github.com/gohtml/elements"
+)
+
+// Choice is an option of a SELECT generated by FormFor.
+type Choice struct {
+	// The value of the field, formatted as FormFor does, e.g. "2" for an int.
+	Value string
+	Label string
+}
+
+// Chooser is implemented by enum-like types. FormFor renders fields of such
+// types as SELECTs with the choices as options.
+type Chooser interface {
+	Choices() []Choice
+}
+
+// FormErrors contains error messages of form fields keyed by field names.
+type FormErrors map[string]string
+
+// FormOptions contains the options of FormFor.
+type FormOptions struct {
+	Method string
+	Action string
+	// The prefix of generated id attributes. Useful when there are more than one
+	// form on a page.
+	IDPrefix string
+	// Error messages to show, e.g. returned by DecodeForm.
+	Errors FormErrors
+	// The text of the submit button. No button is generated if empty.
+	Submit string
+}
+
+// formField is the information of a struct field parsed from its html tag.
+//
+// The tag is a comma-separated list. The first item is the name of the field
+// and the rest are options: required, type=, label=, placeholder=, min=, max=,
+// minlength=, maxlength= and pattern=. Since a pattern may contain commas,
+// pattern= should be the last option and takes the rest of the tag.
+type formField struct {
+	index       int
+	name        string
+	label       string
+	tp          string
+	required    bool
+	placeholder string
+	min         string
+	max         string
+	minLength   string
+	maxLength   string
+	pattern     string
+}
+
+var chooserType = reflect.TypeOf((*Chooser)(nil)).Elem()
+
+func inputTypeOf(t reflect.Type) string {
+	if t.Implements(chooserType) {
+		return "select"
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "checkbox"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "text"
+	}
+	return ""
+}
+
+// formFields returns the form fields of a struct type. Unexported fields,
+// fields of unsupported types and fields tagged with "-" are ignored. Fields
+// whose types don't match their type= options, e.g. a type=checkbox on a
+// string, are ignored too and the first of them is reported by err.
+func formFields(t reflect.Type) (fields []formField, err error) {
+	for i, n := 0, t.NumField(); i < n; i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+		tag := sf.Tag.Get("html")
+		if tag == "-" {
+			continue
+		}
+
+		f := formField{
+			index: i,
+			name:  sf.Name,
+			label: sf.Name,
+			tp:    inputTypeOf(sf.Type),
+		}
+		if f.tp == "" {
+			continue
+		}
+
+		parts := strings.Split(tag, ",")
+		if parts[0] != "" {
+			f.name = parts[0]
+		}
+		for j := 1; j < len(parts); j++ {
+			key, value := parts[j], ""
+			if p := strings.IndexByte(key, '='); p >= 0 {
+				key, value = key[:p], key[p+1:]
+			}
+			switch key {
+			case "required":
+				f.required = true
+			case "type":
+				f.tp = value
+			case "label":
+				f.label = value
+			case "placeholder":
+				f.placeholder = value
+			case "min":
+				f.min = value
+			case "max":
+				f.max = value
+			case "minlength":
+				f.minLength = value
+			case "maxlength":
+				f.maxLength = value
+			case "pattern":
+				f.pattern = strings.Join(append([]string{value}, parts[j+1:]...), ",")
+				j = len(parts)
+			}
+		}
+		if msg := f.checkType(sf.Type); msg != "" {
+			if err == nil {
+				err = fmt.Errorf("html tag of %s.%s: %s", t.Name(), sf.Name, msg)
+			}
+			continue
+		}
+		fields = append(fields, f)
+	}
+	return fields, err
+}
+
+// checkType returns an error message if the control of the field cannot
+// represent values of type t.
+func (f *formField) checkType(t reflect.Type) string {
+	switch f.tp {
+	case "select":
+		if !t.Implements(chooserType) {
+			return "type=select requires a Chooser"
+		}
+	case "checkbox":
+		if t.Kind() != reflect.Bool {
+			return "type=checkbox requires a bool"
+		}
+	}
+	return ""
+}
+
+// formatField formats a field value as a form value. Unlike fmt.Sprint, the
+// String method of the type is not used so that the result can be parsed back.
+func formatField(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits())
+	case reflect.String:
+		return v.String()
+	}
+	return ""
+}
+
+// control creates the control of the field. The returned *Void is the Void
+// part of the control for setting attributes.
+func (f *formField) control(id string, v reflect.Value) (Node, *Void) {
+	var (
+		ctrl Node
+		cv   *Void
+	)
+	switch f.tp {
+	case "select":
+		sel := &Element{Void: Void{tagType: SELECTTag}}
+		cur := formatField(v)
+		for _, c := range v.Interface().(Chooser).Choices() {
+			opt := OPTION(c.Value, c.Label)
+			if c.Value == cur {
+				opt.Attr("selected", "")
+			}
+			sel.Child(opt)
+		}
+		ctrl, cv = sel, &sel.Void
+
+	case "textarea":
+		ta := (&Element{Void: Void{tagType: TEXTAREATag}}).T(formatField(v))
+		ctrl, cv = ta, &ta.Void
+		cv.NonEmptyAttr("placeholder", f.placeholder)
+		cv.NonEmptyAttr("minlength", f.minLength)
+		cv.NonEmptyAttr("maxlength", f.maxLength)
+
+	default:
+		cv = INPUT(f.tp, "", "")
+		ctrl = cv
+		switch f.tp {
+		case "checkbox":
+			cv.Attr("value", "true")
+			if v.Bool() {
+				cv.Attr("checked", "")
+			}
+		case "password":
+			// Never send passwords back.
+		default:
+			cv.NonEmptyAttr("value", formatField(v))
+		}
+		cv.NonEmptyAttr("placeholder", f.placeholder)
+		cv.NonEmptyAttr("min", f.min)
+		cv.NonEmptyAttr("max", f.max)
+		cv.NonEmptyAttr("minlength", f.minLength)
+		cv.NonEmptyAttr("maxlength", f.maxLength)
+		cv.NonEmptyAttr("pattern", f.pattern)
+		if f.tp == "number" && (v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64) {
+			cv.Attr("step", "any")
+		}
+	}
+
+	cv.Attr("id", id).Attr("name", f.name)
+	if f.required {
+		cv.Attr("required", "")
+	}
+	return ctrl, cv
+}
+
+// FormFor creates a FORM element with labeled controls for the fields of v,
+// which must be a struct or a pointer to a struct.
+//
+// Controls are configured by html tags of the fields, e.g.
+//
+//	Email string `html:"email,type=email,required,label=E-mail,maxlength=64"`
+//
+// strings are rendered as text INPUTs, numbers as number INPUTs, bools as
+// checkboxes and Choosers as SELECTs. type=textarea renders a TEXTAREA.
+// Validation options are exported as attributes of the controls. DecodeForm
+// decodes the submitted values with the same rules.
+//
+// Each control is in a DIV with class "field", together with its LABEL and, if
+// opts.Errors contains its name, a SPAN with class "error" containing the
+// message.
+//
+// Fields with type= options not matching their types, e.g. type=select on a
+// type that is not a Chooser, are skipped and the error is reported when the
+// form is rendered, so Render returns it. So is a v of other types or a nil
+// pointer, for which the FORM is empty.
+func FormFor(v interface{}, opts FormOptions) *Element {
+	form := FORM(opts.Method, opts.Action)
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return form.Child(failedNode{fmt.Errorf("FormFor: v should be a struct or a non-nil pointer to a struct, got %T", v)})
+	}
+
+	fields, err := formFields(rv.Type())
+	if err != nil {
+		form.Child(failedNode{fmt.Errorf("FormFor: %v", err)})
+	}
+	for _, f := range fields {
+		id := opts.IDPrefix + f.name
+		ctrl, cv := f.control(id, rv.Field(f.index))
+
+		div := DIV(LABEL(id, T(f.label)), ctrl).AddClass("field")
+		if msg, ok := opts.Errors[f.name]; ok {
+			cv.Attr("aria-invalid", "true").Attr("aria-describedby", id+"-error")
+			div.AddClass("has-error").Child(SPAN(T(msg)).Attr("id", id+"-error").AddClass("error"))
+		}
+		form.Child(div)
+	}
+
+	if opts.Submit != "" {
+		form.Child(BUTTON(T(opts.Submit)).Attr("type", "submit"))
+	}
+	return form
+}
@@ -0,0 +1,111 @@
+package html
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/golangplus/testing/assert"
+)
+
+type color int
+
+func (color) Choices() []Choice {
+	return []Choice{{"0", "Red"}, {"1", "Green"}, {"2", "Blue"}}
+}
+
+type signup struct {
+	Email    string  `html:"email,type=email,required,label=E-mail,maxlength=64"`
+	Password string  `html:"password,type=password,required,minlength=8"`
+	Age      int     `html:"age,min=18,max=130"`
+	Height   float64 `html:"height"`
+	Color    color   `html:"color"`
+	Bio      string  `html:"bio,type=textarea,maxlength=200"`
+	Code     string  `html:"code,pattern=[a-z]{2,4}"`
+	Agree    bool    `html:"agree,required"`
+	Internal string  `html:"-"`
+	hidden   string
+}
+
+func TestFormFields(t *testing.T) {
+	fields, err := formFields(reflect.TypeOf(signup{}))
+	assert.NoError(t, err)
+	assert.Equal(t, "len(fields)", len(fields), 8)
+	assert.Equal(t, "fields[6].pattern", fields[6].pattern, "[a-z]{2,4}")
+	assert.Equal(t, "fields[4].tp", fields[4].tp, "select")
+}
+
+func TestFormFor_invalidType(t *testing.T) {
+	type invalid struct {
+		Name  string `html:"name,type=select"`
+		Agree string `html:"agree,type=checkbox"`
+		Age   int    `html:"age"`
+	}
+
+	var b bytes.Buffer
+	err := Render(&b, FormFor(invalid{Age: 3}, FormOptions{}), DefaultOptions)
+	assert.Error(t, err)
+	assert.Equal(t, "err", err.Error(), "FormFor: html tag of invalid.Name: type=select requires a Chooser")
+
+	form := FormFor(invalid{Age: 3}, FormOptions{})
+	assert.Equal(t, "controls", len(Select(form, "input, select")), 1)
+
+	var v invalid
+	assert.Equal(t, "errs", len(DecodeForm(url.Values{"name": {"x"}, "agree": {"true"}, "age": {"4"}}, &v)), 0)
+	assert.Equal(t, "v", v, invalid{Age: 4})
+}
+
+func TestFormFor(t *testing.T) {
+	form := FormFor(&signup{
+		Email:    "a@example.com",
+		Password: "secret",
+		Age:      20,
+		Color:    1,
+		Bio:      "<hi>",
+		Agree:    true,
+	}, FormOptions{
+		Method:   "POST",
+		Action:   "/signup",
+		IDPrefix: "s-",
+		Errors:   FormErrors{"age": "Too young"},
+		Submit:   "Sign up",
+	})
+
+	assert.StringEqual(t, "form", NodeToHTMLNode(form, RenderOptions{SortAttr: true}),
+		`<form action="/signup" method="POST">`+
+			`<div class="field"><label for="s-email">E-mail</label><input id="s-email" maxlength="64" name="email" required type="email" value="a@example.com"></div>`+
+			`<div class="field"><label for="s-password">Password</label><input id="s-password" minlength="8" name="password" required type="password"></div>`+
+			`<div class="field has-error"><label for="s-age">Age</label><input aria-describedby="s-age-error" aria-invalid="true" id="s-age" max="130" min="18" name="age" type="number" value="20"><span class="error" id="s-age-error">Too young</span></div>`+
+			`<div class="field"><label for="s-height">Height</label><input id="s-height" name="height" step="any" type="number" value="0"></div>`+
+			`<div class="field"><label for="s-color">Color</label><select id="s-color" name="color"><option value="0">Red<option selected value="1">Green<option value="2">Blue</select></div>`+
+			`<div class="field"><label for="s-bio">Bio</label><textarea id="s-bio" maxlength="200" name="bio">&lt;hi&gt;</textarea></div>`+
+			`<div class="field"><label for="s-code">Code</label><input id="s-code" name="code" pattern="[a-z]{2,4}" type="text"></div>`+
+			`<div class="field"><label for="s-agree">Agree</label><input checked id="s-agree" name="agree" required type="checkbox" value="true"></div>`+
+			`<button type="submit">Sign up</button></form>`)
+
+}
+
+func TestFormFor_invalidValue(t *testing.T) {
+	for _, v := range []interface{}{1, (*signup)(nil), nil} {
+		var b bytes.Buffer
+		err := Render(&b, FormFor(v, FormOptions{}), DefaultOptions)
+		assert.Error(t, err)
+		assert.Equal(t, "b.Len()", b.Len(), 0)
+	}
+	assert.Equal(t, "err", Render(ioutil.Discard, FormFor((*signup)(nil), FormOptions{}), DefaultOptions).Error(),
+		"FormFor: v should be a struct or a non-nil pointer to a struct, got *html.signup")
+}
+
+func ExampleFormFor() {
+	type login struct {
+		User     string `html:"user,required,label=User name"`
+		Password string `html:"password,type=password,required"`
+	}
+	form := FormFor(login{User: "gopher"}, FormOptions{Method: "POST", Action: "/login"})
+	fmt.Println(NodeToHTMLNode(form, RenderOptions{SortAttr: true}))
+	// OUTPUT:
+	// <form action="/login" method="POST"><div class="field"><label for="user">User name</label><input id="user" name="user" required type="text" value="gopher"></div><div class="field"><label for="password">Password</label><input id="password" name="password" required type="password"></div></form>
+}
@@ -0,0 +1,45 @@
+package html
+
+import (
+	"fmt"
+	"io"
+
+	. "github.com/gohtml/elements"
+)
+
+// fragmentRoot renders an element with both of its tags.
+type fragmentRoot struct {
+	e *Element
+}
+
+func (f fragmentRoot) Type() TagType {
+	return f.e.Type()
+}
+
+func (f fragmentRoot) WriteTo(b Writer, opt RenderOptions, parent *Element, childIndex int) {
+	f.e.writeTo(b, opt, false, false)
+}
+
+// RenderFragment renders the first node in the tree rooted at root matching the
+// CSS selector, e.g. "#items", into w as a standalone fragment. The tags of the
+// fragment root are never omitted, e.g. an LI or a TBODY rendered alone, since
+// omitted tags could change the meaning out of context. Omission in the
+// fragment follows opt.
+//
+// An error is returned if the selector is invalid or nothing matches. Other
+// errors are reported as Render does.
+func RenderFragment(root Node, selector string, w io.Writer, opt RenderOptions) error {
+	sel, err := ParseSelector(selector)
+	if err != nil {
+		return err
+	}
+	nd := sel.First(root)
+	if nd == nil {
+		return fmt.Errorf("RenderFragment: nothing matches %q", selector)
+	}
+
+	if e, ok := nd.(*Element); ok {
+		nd = fragmentRoot{e}
+	}
+	return Render(w, nd, opt)
+}
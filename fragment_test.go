@@ -0,0 +1,64 @@
+package html
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/golangplus/testing/assert"
+)
+
+func fragmentPage() *Html {
+	h := HTML("en")
+	h.Title("Fragments")
+	h.Body().Child(
+		UL(LI(T("a")), LI(T("b")).Attr("id", "b")).Attr("id", "items"),
+		TABLE(TBODY(TR(TD(T("1")), TD(T("2"))))),
+		P(T("text")),
+		DL(DT(T("t")), DD(T("d"))),
+	)
+	return h
+}
+
+func TestRenderFragment(t *testing.T) {
+	for _, c := range []struct {
+		sel, exp string
+	}{
+		{"#items", "<ul id=\"items\"><li>a<li id=\"b\">b</ul>"},
+		{"#b", `<li id="b">b</li>`},
+		{"td", `<td>1</td>`},
+		{"tbody", `<tbody><tr><td>1<td>2</tbody>`},
+		{"body > p", `<p>text</p>`},
+		{"dt", `<dt>t</dt>`},
+		{"title", `<title>Fragments</title>`},
+	} {
+		var b bytes.Buffer
+		assert.NoError(t, RenderFragment(fragmentPage(), c.sel, &b, DefaultOptions))
+		assert.Equal(t, c.sel, b.String(), c.exp)
+	}
+
+	var b bytes.Buffer
+	assert.True(t, "missing", RenderFragment(fragmentPage(), "#missing", &b, DefaultOptions) != nil)
+	assert.True(t, "invalid", RenderFragment(fragmentPage(), "li[", &b, DefaultOptions) != nil)
+	assert.Equal(t, "written", b.Len(), 0)
+}
+
+func TestRender_standalone(t *testing.T) {
+	// Elements whose end tags depend on their siblings keep them when rendered
+	// without a parent.
+	for _, e := range []*Element{P(T("p")), TD(T("td")), DT(T("dt")), DD(T("dd")), OPTION("v", "o"), TR(), THEAD()} {
+		s := string(NodeToHTMLNode(e, DefaultOptions))
+		assert.True(t, s, strings.HasSuffix(s, "</"+string(e.Name())+">"))
+	}
+}
+
+func ExampleRenderFragment() {
+	page := HTML("en")
+	page.Body().Child(UL(LI(T("Home")).Attr("id", "home"), LI(T("About"))))
+	RenderFragment(page, "#home", os.Stdout, DefaultOptions)
+	fmt.Println()
+	// OUTPUT:
+	// <li id="home">Home</li>
+}
@@ -0,0 +1,394 @@
+package ghtml
+
+import (
+	"bytes"
+	"fmt"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gohtml/elements"
+	"github.com/gohtml/html/internal/tags"
+)
+
+// Names of the packages imported by the generated code. They are unlikely to
+// conflict with the imports of the Go code in templates.
+const (
+	htmlPkg     = "ghtml"
+	elementsPkg = "gelements"
+	fmtPkg      = "gfmt"
+)
+
+// Elements whose whitespace is kept.
+var preTags = map[string]bool{
+	"pre": true, "textarea": true, "script": true, "style": true,
+}
+
+// Elements whose contents are not escaped.
+var scriptTags = map[string]bool{
+	"script": true, "style": true,
+}
+
+type generator struct {
+	file string
+	b    bytes.Buffer
+	vars int
+	// The indentation of the generated code. The code is not formatted by
+	// go/format, which would separate line directives from the code.
+	depth int
+
+	usesElements bool
+	usesFmt      bool
+	// The first error found.
+	err error
+}
+
+// println writes an indented line of code.
+func (g *generator) println(format string, args ...interface{}) {
+	g.b.WriteString(strings.Repeat("\t", g.depth))
+	fmt.Fprintf(&g.b, format, args...)
+	g.b.WriteByte('\n')
+}
+
+// blankLine separates declarations with a blank line.
+func (g *generator) blankLine() {
+	if g.b.Len() > 0 && !bytes.HasSuffix(g.b.Bytes(), []byte("\n\n")) {
+		g.b.WriteByte('\n')
+	}
+}
+
+// lineDirective returns an inline line directive mapping the following code
+// to pos.
+func (g *generator) lineDirective(pos Pos) string {
+	return fmt.Sprintf("/*line %s:%d:%d*/", g.file, pos.Line, pos.Col)
+}
+
+// check parses prefix+code+suffix as a Go file and records the first error
+// in code at its position in the template. prefix must be on a single line.
+func (g *generator) check(prefix, code, suffix string, pos Pos) {
+	if g.err != nil {
+		return
+	}
+
+	_, err := parser.ParseFile(token.NewFileSet(), "", prefix+code+suffix, 0)
+	list, ok := err.(scanner.ErrorList)
+	if !ok || len(list) == 0 {
+		return
+	}
+	e := list[0]
+	errPos := Pos{pos.Line + e.Pos.Line - 1, e.Pos.Column}
+	if e.Pos.Line == 1 {
+		errPos.Col = pos.Col + e.Pos.Column - 1 - len(prefix)
+		if errPos.Col < pos.Col {
+			errPos.Col = pos.Col
+		}
+	}
+	g.err = &Error{File: g.file, Pos: errPos, Msg: e.Msg}
+}
+
+// checkCode checks a Go expression or clause in the template, which must not
+// be empty.
+func (g *generator) checkCode(prefix, code, suffix string, pos Pos) {
+	if g.err == nil && strings.TrimSpace(code) == "" {
+		g.err = &Error{File: g.file, Pos: pos, Msg: "missing Go code"}
+	}
+	g.check(prefix, code, suffix, pos)
+}
+
+// goExpr returns the code of a Go expression in the template with its position.
+func (g *generator) goExpr(code string, pos Pos) string {
+	g.checkCode("package p; var _ = (", code, ")", pos)
+	return g.lineDirective(pos) + code
+}
+
+// context is the context of a list of nodes.
+type context struct {
+	// The variable of the parent element, or empty for the top level.
+	parent string
+	// Whether whitespace is kept.
+	pre bool
+	// Whether the texts are not escaped.
+	script bool
+	// Whether it is in a foreign element, e.g. SVG.
+	foreign bool
+}
+
+// add appends a node to the parent.
+func (g *generator) add(ctx context, code string) {
+	if ctx.parent == "" {
+		g.println("_nodes = append(_nodes, %s)", code)
+		return
+	}
+	g.println("%s.Child(%s)", ctx.parent, code)
+}
+
+// isBlock returns true if nd is a block-level element, or an {if} or {for}
+// block, line breaks around which are for formatting.
+func isBlock(nd tnode) bool {
+	switch nd := nd.(type) {
+	case *elemNode:
+		return tags.Block[nd.name]
+	case *ifNode, *forNode:
+		return true
+	}
+	return false
+}
+
+func (g *generator) nodes(ctx context, nodes []tnode) {
+	for i, nd := range nodes {
+		switch nd := nd.(type) {
+		case *textNode:
+			text := nd.text
+			if !ctx.pre {
+				text = tags.CollapseSpaces(text, i == 0, i == len(nodes)-1)
+				if text == " " && strings.ContainsAny(nd.text, "\n\r") &&
+					(i > 0 && isBlock(nodes[i-1]) || i+1 < len(nodes) && isBlock(nodes[i+1])) {
+					text = ""
+				}
+			}
+			if text == "" {
+				continue
+			}
+			if ctx.script {
+				g.add(ctx, htmlPkg+".HTMLNode("+strconv.Quote(text)+")")
+			} else {
+				g.add(ctx, htmlPkg+".T("+strconv.Quote(text)+")")
+			}
+
+		case *exprNode:
+			if nd.node {
+				g.add(ctx, g.goExpr(nd.code, nd.pos))
+			} else {
+				g.add(ctx, htmlPkg+`.Tf("%v", `+g.goExpr(nd.code, nd.pos)+")")
+			}
+
+		case *elemNode:
+			g.add(ctx, g.element(ctx, nd))
+
+		case *ifNode:
+			for i, br := range nd.branches {
+				switch {
+				case i == 0:
+					g.checkCode("package p; func _() { if ", br.cond, " {} }", br.pos)
+					g.println("if %s%s {", g.lineDirective(br.pos), br.cond)
+				case br.cond != "":
+					g.checkCode("package p; func _() { if ", br.cond, " {} }", br.pos)
+					g.println("} else if %s%s {", g.lineDirective(br.pos), br.cond)
+				default:
+					g.println("} else {")
+				}
+				g.depth++
+				g.nodes(ctx, br.body)
+				g.depth--
+			}
+			g.println("}")
+
+		case *forNode:
+			g.checkCode("package p; func _() { for ", nd.clause, " {} }", nd.pos)
+			g.println("for %s%s {", g.lineDirective(nd.pos), nd.clause)
+			g.depth++
+			g.nodes(ctx, nd.body)
+			g.depth--
+			g.println("}")
+		}
+	}
+}
+
+// attrValue returns the code of the value of an attribute.
+func (g *generator) attrValue(a attrNode) string {
+	switch {
+	case len(a.parts) == 0:
+		return `""`
+	case len(a.parts) == 1 && !a.parts[0].isExpr:
+		return strconv.Quote(a.parts[0].text)
+	case len(a.parts) == 1:
+		g.usesFmt = true
+		return fmtPkg + ".Sprint(" + g.goExpr(a.parts[0].text, a.parts[0].pos) + ")"
+	}
+
+	g.usesFmt = true
+	var (
+		format strings.Builder
+		args   []string
+	)
+	for _, part := range a.parts {
+		if part.isExpr {
+			format.WriteString("%v")
+			args = append(args, g.goExpr(part.text, part.pos))
+		} else {
+			format.WriteString(strings.Replace(part.text, "%", "%%", -1))
+		}
+	}
+	return fmtPkg + ".Sprintf(" + strconv.Quote(format.String()) + ", " + strings.Join(args, ", ") + ")"
+}
+
+// element generates the code building an element and returns its variable.
+func (g *generator) element(ctx context, el *elemNode) string {
+	g.vars++
+	v := "_e" + strconv.Itoa(g.vars)
+
+	tp, known := tags.Types[el.name]
+	switch {
+	case ctx.foreign || !known:
+		ctx.foreign = true
+		g.println("%s := %s.FOREIGN(%q)", v, htmlPkg, el.name)
+	case tags.Void[el.name]:
+		g.usesElements = true
+		g.println("%s := %s.NewVoid(%s.%sTag)", v, htmlPkg, elementsPkg, strings.ToUpper(elements.TagNames[tp]))
+	default:
+		g.usesElements = true
+		g.println("%s := %s.NewElement(%s.%sTag)", v, htmlPkg, elementsPkg, strings.ToUpper(elements.TagNames[tp]))
+	}
+
+	for _, a := range el.attrs {
+		g.println("%s.Attr(%q, %s)", v, a.name, g.attrValue(a))
+	}
+
+	ctx.parent = v
+	ctx.pre = ctx.pre || preTags[el.name]
+	ctx.script = scriptTags[el.name]
+	g.nodes(ctx, el.children)
+	return v
+}
+
+// root returns the only element of a template body, if any.
+func root(body []tnode) *elemNode {
+	var el *elemNode
+	for i, nd := range body {
+		switch nd := nd.(type) {
+		case *textNode:
+			if tags.CollapseSpaces(nd.text, i == 0, i == len(body)-1) != "" {
+				return nil
+			}
+		case *elemNode:
+			if el != nil {
+				return nil
+			}
+			el = nd
+		default:
+			return nil
+		}
+	}
+	return el
+}
+
+func (g *generator) template(t *template) {
+	g.check("package p; func _(", t.params, ") {}", t.pos)
+	g.blankLine()
+	g.println("func %s(%s%s) %s.Node {", t.name, g.lineDirective(t.pos), t.params, htmlPkg)
+	g.vars = 0
+	g.depth++
+	if el := root(t.body); el != nil {
+		g.println("return %s", g.element(context{}, el))
+	} else {
+		g.println("var _nodes %s.Nodes", htmlPkg)
+		g.nodes(context{}, t.body)
+		g.println("return _nodes")
+	}
+	g.depth--
+	g.println("}")
+}
+
+// packageClauseEnd returns the offset after the line of the package clause
+// in code, or -1 if code doesn't start with a package clause.
+func packageClauseEnd(code string) int {
+	fset := token.NewFileSet()
+	tf := fset.AddFile("", fset.Base(), len(code))
+	var s scanner.Scanner
+	s.Init(tf, []byte(code), nil, 0)
+	if _, tok, _ := s.Scan(); tok != token.PACKAGE {
+		return -1
+	}
+	pos, tok, _ := s.Scan()
+	if tok != token.IDENT {
+		return -1
+	}
+	end := tf.Offset(pos)
+	if i := strings.IndexByte(code[end:], '\n'); i >= 0 {
+		return end + i + 1
+	}
+	return len(code)
+}
+
+// Generate compiles a .ghtml file into Go code. filename is used in errors
+// and in line directives of the generated code, so it should be relative to
+// the directory of the generated file.
+func Generate(filename string, src []byte) ([]byte, error) {
+	f, err := parseFile(filename, string(src))
+	if err != nil {
+		return nil, err
+	}
+	if len(f.order) == 0 || f.order[0] {
+		return nil, &Error{File: filename, Pos: Pos{1, 1}, Msg: "missing package clause"}
+	}
+
+	head := f.chunks[0]
+	end := packageClauseEnd(head.code)
+	if end < 0 {
+		return nil, &Error{File: filename, Pos: Pos{head.line, 1}, Msg: "missing package clause"}
+	}
+	f.chunks[0] = goChunk{
+		code: head.code[end:],
+		line: head.line + strings.Count(head.code[:end], "\n"),
+	}
+
+	g := &generator{file: filename}
+	chunks, templates := f.chunks, f.templates
+	for _, isTemplate := range f.order {
+		if isTemplate {
+			g.template(templates[0])
+			templates = templates[1:]
+			continue
+		}
+		if code := chunks[0].code; code != "" {
+			code = strings.TrimLeft(code, "\n")
+			if code == "" {
+				chunks = chunks[1:]
+				continue
+			}
+			g.blankLine()
+			g.println("//line %s:%d:1", filename, chunks[0].line+strings.Count(chunks[0].code, "\n")-strings.Count(code, "\n"))
+			g.b.WriteString(code)
+			if !strings.HasSuffix(code, "\n") {
+				g.b.WriteByte('\n')
+			}
+		}
+		chunks = chunks[1:]
+	}
+	if g.err != nil {
+		return nil, g.err
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by ghtml from %s. DO NOT EDIT.\n\n", filepath.Base(filename))
+	b.WriteString(head.code[:end])
+	if !strings.HasSuffix(head.code[:end], "\n") {
+		b.WriteByte('\n')
+	}
+	b.WriteString("\nimport (\n")
+	if g.usesFmt {
+		fmt.Fprintf(&b, "\t%s \"fmt\"\n", fmtPkg)
+	}
+	if g.usesElements {
+		fmt.Fprintf(&b, "\t%s \"github.com/gohtml/elements\"\n", elementsPkg)
+	}
+	fmt.Fprintf(&b, "\t%s \"github.com/gohtml/html\"\n)\n\n", htmlPkg)
+	b.Write(g.b.Bytes())
+
+	// Errors in the Go code are reported at their positions in the template.
+	if _, err := parser.ParseFile(token.NewFileSet(), filename, b.Bytes(), 0); err != nil {
+		if list, ok := err.(scanner.ErrorList); ok && len(list) > 0 {
+			e := list[0]
+			pos := Pos{e.Pos.Line, e.Pos.Column}
+			if pos.Col < 1 {
+				pos.Col = 1
+			}
+			return nil, &Error{File: filename, Pos: pos, Msg: e.Msg}
+		}
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
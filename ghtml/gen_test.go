@@ -0,0 +1,216 @@
+package ghtml
+
+import (
+	"testing"
+
+	"github.com/golangplus/testing/assert"
+)
+
+func TestGenerate(t *testing.T) {
+	src := `package views
+
+template Hello(name string, items []string) {
+	<div class="hello">
+		<h1 title="Hi {name}">Hello, {name}!</h1>
+		{if len(items) > 0}
+			<ul>
+			{for _, it := range items}
+				<li>{it}</li>
+			{end}
+			</ul>
+		{else}
+			<br>
+		{end}
+	</div>
+}
+
+func other() {}
+
+template Pair(a, b html.Node) {
+	{!a}
+	<pre>  {{ x </pre>
+	{!b}
+}
+`
+	out, err := Generate("hello.ghtml", []byte(src))
+	assert.NoError(t, err)
+	assert.StringEqual(t, "out", string(out), `// Code generated by ghtml from hello.ghtml. DO NOT EDIT.
+
+package views
+
+import (
+	gfmt "fmt"
+	gelements "github.com/gohtml/elements"
+	ghtml "github.com/gohtml/html"
+)
+
+func Hello(/*line hello.ghtml:3:16*/name string, items []string) ghtml.Node {
+	_e1 := ghtml.NewElement(gelements.DIVTag)
+	_e1.Attr("class", "hello")
+	_e2 := ghtml.NewElement(gelements.H1Tag)
+	_e2.Attr("title", gfmt.Sprintf("Hi %v", /*line hello.ghtml:5:18*/name))
+	_e2.Child(ghtml.T("Hello, "))
+	_e2.Child(ghtml.Tf("%v", /*line hello.ghtml:5:33*/name))
+	_e2.Child(ghtml.T("!"))
+	_e1.Child(_e2)
+	if /*line hello.ghtml:6:7*/len(items) > 0 {
+		_e3 := ghtml.NewElement(gelements.ULTag)
+		for /*line hello.ghtml:8:9*/_, it := range items {
+			_e4 := ghtml.NewElement(gelements.LITag)
+			_e4.Child(ghtml.Tf("%v", /*line hello.ghtml:9:10*/it))
+			_e3.Child(_e4)
+		}
+		_e1.Child(_e3)
+	} else {
+		_e5 := ghtml.NewVoid(gelements.BRTag)
+		_e1.Child(_e5)
+	}
+	return _e1
+}
+
+//line hello.ghtml:18:1
+func other() {}
+
+func Pair(/*line hello.ghtml:20:15*/a, b html.Node) ghtml.Node {
+	var _nodes ghtml.Nodes
+	_nodes = append(_nodes, /*line hello.ghtml:21:4*/a)
+	_e1 := ghtml.NewElement(gelements.PRETag)
+	_e1.Child(ghtml.T("  { x "))
+	_nodes = append(_nodes, _e1)
+	_nodes = append(_nodes, /*line hello.ghtml:23:4*/b)
+	return _nodes
+}
+`)
+}
+
+func TestGenerate_foreign(t *testing.T) {
+	out, err := Generate("icon.ghtml", []byte(`package views
+template Icon() {
+	<svg viewBox="0 0 8 8"><circle r="4"/><my-el></my-el></svg>
+}
+`))
+	assert.NoError(t, err)
+	assert.StringEqual(t, "out", string(out), `// Code generated by ghtml from icon.ghtml. DO NOT EDIT.
+
+package views
+
+import (
+	ghtml "github.com/gohtml/html"
+)
+
+func Icon(/*line icon.ghtml:2:15*/) ghtml.Node {
+	_e1 := ghtml.FOREIGN("svg")
+	_e1.Attr("viewBox", "0 0 8 8")
+	_e2 := ghtml.FOREIGN("circle")
+	_e2.Attr("r", "4")
+	_e1.Child(_e2)
+	_e3 := ghtml.FOREIGN("my-el")
+	_e1.Child(_e3)
+	return _e1
+}
+`)
+}
+
+func TestGenerate_entities(t *testing.T) {
+	out, err := Generate("e.ghtml", []byte(`package views
+template E(x string) {
+	<p title="a &amp; {x} &quot;b&quot;" lang=en&#45;US>Tom &amp; Jerry&nbsp;!<textarea>&lt;</textarea></p>
+}
+`))
+	assert.NoError(t, err)
+	assert.StringEqual(t, "out", string(out), `// Code generated by ghtml from e.ghtml. DO NOT EDIT.
+
+package views
+
+import (
+	gfmt "fmt"
+	gelements "github.com/gohtml/elements"
+	ghtml "github.com/gohtml/html"
+)
+
+func E(/*line e.ghtml:2:12*/x string) ghtml.Node {
+	_e1 := ghtml.NewElement(gelements.PTag)
+	_e1.Attr("title", gfmt.Sprintf("a & %v \"b\"", /*line e.ghtml:3:21*/x))
+	_e1.Attr("lang", "en-US")
+	_e1.Child(ghtml.T("Tom & Jerry\u00a0!"))
+	_e2 := ghtml.NewElement(gelements.TEXTAREATag)
+	_e2.Child(ghtml.T("<"))
+	_e1.Child(_e2)
+	return _e1
+}
+`)
+}
+
+func TestGenerate_templateEnd(t *testing.T) {
+	out, err := Generate("code.ghtml", []byte(`package views
+template Code() {
+	<style>
+a { color: red;
+}
+	</style>
+	<pre>
+}
+	</pre>
+	<b>x</b>
+	<i>y</i>
+}
+`))
+	assert.NoError(t, err)
+	assert.StringEqual(t, "out", string(out), `// Code generated by ghtml from code.ghtml. DO NOT EDIT.
+
+package views
+
+import (
+	gelements "github.com/gohtml/elements"
+	ghtml "github.com/gohtml/html"
+)
+
+func Code(/*line code.ghtml:2:15*/) ghtml.Node {
+	var _nodes ghtml.Nodes
+	_e1 := ghtml.NewElement(gelements.STYLETag)
+	_e1.Child(ghtml.HTMLNode("\na { color: red;\n}\n\t"))
+	_nodes = append(_nodes, _e1)
+	_e2 := ghtml.NewElement(gelements.PRETag)
+	_e2.Child(ghtml.T("\n}\n\t"))
+	_nodes = append(_nodes, _e2)
+	_e3 := ghtml.NewElement(gelements.BTag)
+	_e3.Child(ghtml.T("x"))
+	_nodes = append(_nodes, _e3)
+	_nodes = append(_nodes, ghtml.T(" "))
+	_e4 := ghtml.NewElement(gelements.ITag)
+	_e4.Child(ghtml.T("y"))
+	_nodes = append(_nodes, _e4)
+	return _nodes
+}
+`)
+}
+
+func TestGenerate_errors(t *testing.T) {
+	for _, c := range []struct {
+		src string
+		err string
+	}{
+		{"template A() {\n}\n", "a.ghtml:1:1: missing package clause"},
+		{"package a\ntemplate A() {\n<p>\n", `a.ghtml:2:1: template A is not closed by a line of "}"`},
+		{"package a\ntemplate A() {\n  <p>{x +}</p>\n}\n", "a.ghtml:3:10: expected operand, found ')'"},
+		{"package a\ntemplate A() {\n<a href={)}>x</a>\n}\n", "a.ghtml:3:10: expected operand, found ')'"},
+		{"package a\ntemplate A() {\n{if x}<p>x</p>\n}\n", "a.ghtml:3:1: {if} is not closed by {end}"},
+		{"package a\ntemplate A() {\n<div><p>x</div>\n}\n", "a.ghtml:3:10: unexpected </div> in <p>"},
+		{"package a\ntemplate A() {\n<p>{}</p>\n}\n", "a.ghtml:3:5: missing Go code"},
+		{"package a\ntemplate A() {\n<p>{ x +}</p>\n}\n", "a.ghtml:3:9: expected operand, found ')'"},
+		{"package a\ntemplate A() {\n<p>é{x +}</p>\n}\n", "a.ghtml:3:10: expected operand, found ')'"},
+		{"package a\ntemplate A() {\n<p>x</p>\n{if x}\n}\n", "a.ghtml:4:1: {if} is not closed by {end}"},
+		{"package a\ntemplate A(a int,) {\n}\n", ""},
+		{"package a\ntemplate A(a in t) {\n}\n", "a.ghtml:2:17: missing ',' in parameter list"},
+		{"package a\ntemplate A() {\n}\n\nvar x = )\n", "a.ghtml:5:9: expected operand, found ')'"},
+	} {
+		_, err := Generate("a.ghtml", []byte(c.src))
+		if c.err == "" {
+			assert.NoError(t, err)
+			continue
+		}
+		if assert.Error(t, err) {
+			assert.Equal(t, c.src, err.Error(), c.err)
+		}
+	}
+}
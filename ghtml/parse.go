@@ -0,0 +1,653 @@
+// Package ghtml compiles .ghtml templates into Go code building trees with
+// package github.com/gohtml/html.
+//
+// A .ghtml file is a Go file containing templates. A template starts with a
+// line like
+//
+//	template Card(title string, items []Item) {
+//
+// and ends with the first line containing only "}" outside elements, {if} and
+// {for} blocks and braces. Code outside templates is copied as it is. The body
+// of a template is markup with Go expressions in braces:
+//
+//	<div class="card">
+//		<h2 id={id}>Hello, {name}!</h2>
+//		{if len(items) == 0}
+//			<p>No items</p>
+//		{else}
+//			<ul>
+//			{for _, it := range items}
+//				<li class="item {it.Kind}">{it.Name}</li>
+//			{end}
+//			</ul>
+//		{end}
+//		{!footer()}
+//	</div>
+//
+// {expr} is rendered as an escaped text of fmt.Sprint(expr), {!expr} inserts
+// expr, which must be an html.Node, as a child, and {{ is a literal brace.
+// Attribute values may be literals, {expr}, or quoted literals containing
+// {expr}s. Attributes without values are rendered as empty. Elements must be
+// closed explicitly, except void elements like <br>. Whitespace in texts is
+// collapsed, except in PRE, TEXTAREA, SCRIPT and STYLE, and dropped if it
+// contains line breaks next to block-level elements or blocks. The contents of
+// TEXTAREA, SCRIPT and STYLE are literal texts without expressions. Character
+// references, e.g. &amp;, are decoded in texts, except in SCRIPT and STYLE, and
+// in attribute values. Comments are dropped.
+//
+// A template compiles into a function with the same parameters returning an
+// html.Node: the root element if there is only one, or html.Nodes otherwise.
+// Expressions are checked by the Go parser during generation, and the
+// generated code contains line directives so that errors reported by the
+// compiler point back to the template.
+package ghtml
+
+import (
+	"fmt"
+	stdhtml "html"
+	"regexp"
+	"strings"
+
+	"github.com/gohtml/html/internal/tags"
+)
+
+// Pos is a position in a template file. Col is counted in bytes as by the Go
+// compiler.
+type Pos struct {
+	Line, Col int
+}
+
+// advancePos returns the position after s, which starts at pos.
+func advancePos(pos Pos, s string) Pos {
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\n' {
+			pos.Line, pos.Col = pos.Line+1, 1
+		} else {
+			pos.Col++
+		}
+	}
+	return pos
+}
+
+// Error is an error in a template file.
+type Error struct {
+	File string
+	Pos  Pos
+	Msg  string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Pos.Line, e.Pos.Col, e.Msg)
+}
+
+// Nodes of a parsed template.
+type (
+	tnode interface{}
+
+	textNode struct {
+		text string
+	}
+
+	exprNode struct {
+		code string
+		pos  Pos
+		// {!expr}
+		node bool
+	}
+
+	// A part of an attribute value, either a literal or an expression.
+	attrPart struct {
+		text   string
+		isExpr bool
+		pos    Pos
+	}
+
+	attrNode struct {
+		name  string
+		parts []attrPart
+	}
+
+	elemNode struct {
+		name     string
+		pos      Pos
+		attrs    []attrNode
+		children []tnode
+	}
+
+	ifBranch struct {
+		// Empty for the else branch.
+		cond string
+		pos  Pos
+		body []tnode
+	}
+
+	ifNode struct {
+		branches []ifBranch
+	}
+
+	forNode struct {
+		clause string
+		pos    Pos
+		body   []tnode
+	}
+)
+
+// goChunk is Go code outside templates.
+type goChunk struct {
+	code string
+	line int
+}
+
+type template struct {
+	name   string
+	params string
+	// The position of params.
+	pos  Pos
+	body []tnode
+}
+
+type file struct {
+	name      string
+	chunks    []goChunk
+	templates []*template
+	// The order of chunks and templates, true for templates.
+	order []bool
+}
+
+var templateStartRegexp = regexp.MustCompile(`^template\s+([A-Za-z_][A-Za-z0-9_]*)\s*\((.*)\)\s*\{\s*$`)
+
+// Elements whose contents are texts only. Markups and expressions in them are
+// not parsed.
+var rawTextTags = map[string]bool{
+	"textarea": true, "script": true, "style": true,
+}
+
+// parseFile splits a .ghtml file into Go chunks and templates.
+func parseFile(name, src string) (*file, error) {
+	f := &file{name: name}
+	lines := strings.SplitAfter(src, "\n")
+	var chunk strings.Builder
+	chunkLine := 1
+	flush := func() {
+		if chunk.Len() > 0 {
+			f.chunks = append(f.chunks, goChunk{code: chunk.String(), line: chunkLine})
+			f.order = append(f.order, false)
+			chunk.Reset()
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], "\r\n")
+		m := templateStartRegexp.FindStringSubmatchIndex(line)
+		if m == nil {
+			if chunk.Len() == 0 {
+				chunkLine = i + 1
+			}
+			chunk.WriteString(lines[i])
+			continue
+		}
+		flush()
+
+		start := i
+		notClosed := &Error{File: name, Pos: Pos{start + 1, 1}, Msg: "template " + line[m[2]:m[3]] + " is not closed by a line of \"}\""}
+		closed := false
+		for _, l := range lines[i+1:] {
+			closed = closed || isTemplateEnd(l)
+		}
+		if !closed {
+			return nil, notClosed
+		}
+
+		p := &bodyParser{file: name, src: strings.Join(lines[i+1:], ""), line: start + 2, col: 1}
+		nodes, err := p.parseBody()
+		if err != nil {
+			return nil, err
+		}
+		if !p.closed {
+			return nil, notClosed
+		}
+		// Skip the line of "}".
+		i = p.line - 1
+		f.templates = append(f.templates, &template{
+			name:   line[m[2]:m[3]],
+			params: line[m[4]:m[5]],
+			pos:    Pos{start + 1, m[4] + 1},
+			body:   nodes,
+		})
+		f.order = append(f.order, true)
+	}
+	flush()
+	return f, nil
+}
+
+// isTemplateEnd returns true if line contains only "}".
+func isTemplateEnd(line string) bool {
+	return strings.TrimRight(line, " \t\r\n") == "}"
+}
+
+// bodyParser parses the body of a template.
+type bodyParser struct {
+	file      string
+	src       string
+	pos       int
+	line, col int
+	// The number of open elements and blocks.
+	depth int
+	// Whether the line of "}" ending the template is reached.
+	closed bool
+}
+
+func (p *bodyParser) errorf(pos Pos, format string, args ...interface{}) error {
+	return &Error{File: p.file, Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *bodyParser) curPos() Pos {
+	return Pos{p.line, p.col}
+}
+
+func (p *bodyParser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *bodyParser) advance(n int) {
+	pos := advancePos(p.curPos(), p.src[p.pos:p.pos+n])
+	p.line, p.col = pos.Line, pos.Col
+	p.pos += n
+}
+
+// atTemplateEnd returns true if the parser is at the start of the line of "}"
+// ending the template.
+func (p *bodyParser) atTemplateEnd() bool {
+	if p.depth > 0 || p.col != 1 {
+		return false
+	}
+	line := p.src[p.pos:]
+	if i := strings.IndexByte(line, '\n'); i >= 0 {
+		line = line[:i]
+	}
+	return isTemplateEnd(line)
+}
+
+func (p *bodyParser) hasPrefix(s string) bool {
+	return strings.HasPrefix(p.src[p.pos:], s)
+}
+
+// The kinds of block ends returned by parseNodes.
+const (
+	endEOF = iota
+	endElse
+	endEnd
+	endTag
+)
+
+// parseBody parses the whole body.
+func (p *bodyParser) parseBody() ([]tnode, error) {
+	nodes, end, err := p.parseNodes("")
+	if err != nil {
+		return nil, err
+	}
+	if end.kind != endEOF {
+		return nil, p.errorf(end.pos, "unexpected %s", end.what)
+	}
+	p.closed = p.atTemplateEnd()
+	return nodes, nil
+}
+
+type blockEnd struct {
+	kind int
+	pos  Pos
+	what string
+	// The condition of {else if cond} and its position.
+	cond    string
+	condPos Pos
+}
+
+// codeAt returns code trimmed of spaces and its position, where code is the
+// content of the braces starting at brace.
+func codeAt(code string, brace Pos) (string, Pos) {
+	trimmed := strings.TrimSpace(code)
+	if trimmed == "" {
+		return "", advancePos(brace, "{")
+	}
+	return trimmed, advancePos(brace, "{"+code[:strings.Index(code, trimmed)])
+}
+
+// restAt returns the position of rest, a suffix of code after its keyword, in
+// the same way as codeAt.
+func restAt(code, rest string, brace Pos) Pos {
+	if rest == "" {
+		return advancePos(brace, "{")
+	}
+	return advancePos(brace, "{"+code[:strings.LastIndex(code, rest)])
+}
+
+// parseNodes parses nodes until the end of the input, {else}, {end} or the
+// end tag of the element. raw is the name of the element if its content is
+// literal.
+func (p *bodyParser) parseNodes(raw string) ([]tnode, blockEnd, error) {
+	var nodes []tnode
+	for !p.eof() && !p.atTemplateEnd() {
+		start := p.curPos()
+		switch {
+		case raw != "":
+			end := strings.Index(p.src[p.pos:], "</"+raw)
+			if end < 0 {
+				return nil, blockEnd{}, p.errorf(start, "<%s> is not closed", raw)
+			}
+			if end > 0 {
+				text := p.src[p.pos : p.pos+end]
+				if raw == "textarea" {
+					// TEXTAREA is escapable unlike SCRIPT and STYLE.
+					text = stdhtml.UnescapeString(text)
+				}
+				nodes = appendText(nodes, text)
+				p.advance(end)
+			}
+			raw = ""
+
+		case p.hasPrefix("<!--"):
+			end := strings.Index(p.src[p.pos:], "-->")
+			if end < 0 {
+				return nil, blockEnd{}, p.errorf(start, "comment is not closed")
+			}
+			p.advance(end + 3)
+
+		case p.hasPrefix("</"):
+			p.advance(2)
+			name := p.name()
+			p.skipSpaces()
+			if !p.hasPrefix(">") {
+				return nil, blockEnd{}, p.errorf(p.curPos(), "expected '>'")
+			}
+			p.advance(1)
+			return nodes, blockEnd{kind: endTag, pos: start, what: "</" + name + ">"}, nil
+
+		case p.hasPrefix("<") && p.pos+1 < len(p.src) && isNameStart(p.src[p.pos+1]):
+			el, err := p.parseElement()
+			if err != nil {
+				return nil, blockEnd{}, err
+			}
+			nodes = append(nodes, el)
+
+		case p.hasPrefix("{{"):
+			p.advance(2)
+			nodes = appendText(nodes, "{")
+
+		case p.hasPrefix("{"):
+			code, err := p.braced()
+			if err != nil {
+				return nil, blockEnd{}, err
+			}
+			kw, rest := splitKeyword(code)
+			switch kw {
+			case "if":
+				n, err := p.parseIf(rest, start, restAt(code, rest, start))
+				if err != nil {
+					return nil, blockEnd{}, err
+				}
+				nodes = append(nodes, n)
+			case "for":
+				p.depth++
+				body, end, err := p.parseNodes("")
+				p.depth--
+				if err != nil {
+					return nil, blockEnd{}, err
+				}
+				if end.kind != endEnd {
+					return nil, blockEnd{}, p.errorf(start, "{for} is not closed by {end}")
+				}
+				nodes = append(nodes, &forNode{clause: rest, pos: restAt(code, rest, start), body: body})
+			case "else":
+				cond := ""
+				if k, c := splitKeyword(rest); k == "if" {
+					cond = c
+				} else if rest != "" {
+					return nil, blockEnd{}, p.errorf(start, "unexpected %q after else", rest)
+				}
+				return nodes, blockEnd{kind: endElse, pos: start, what: "{else}", cond: cond, condPos: restAt(code, cond, start)}, nil
+			case "end":
+				return nodes, blockEnd{kind: endEnd, pos: start, what: "{end}"}, nil
+			default:
+				if strings.HasPrefix(code, "!") {
+					code, pos := codeAt(code[1:], advancePos(start, "!"))
+					nodes = append(nodes, &exprNode{code: code, pos: pos, node: true})
+				} else {
+					code, pos := codeAt(code, start)
+					nodes = append(nodes, &exprNode{code: code, pos: pos})
+				}
+			}
+
+		default:
+			// At the top level, texts are split after line breaks since the
+			// next line may end the template.
+			end := p.pos + 1
+			for end < len(p.src) && p.src[end] != '<' && p.src[end] != '{' && (p.depth > 0 || p.src[end-1] != '\n') {
+				end++
+			}
+			nodes = appendText(nodes, stdhtml.UnescapeString(p.src[p.pos:end]))
+			p.advance(end - p.pos)
+		}
+	}
+	return nodes, blockEnd{kind: endEOF, pos: p.curPos(), what: "end of template"}, nil
+}
+
+// appendText appends a text to nodes, merging it into the last text node.
+func appendText(nodes []tnode, text string) []tnode {
+	if len(nodes) > 0 {
+		if t, ok := nodes[len(nodes)-1].(*textNode); ok {
+			t.text += text
+			return nodes
+		}
+	}
+	return append(nodes, &textNode{text: text})
+}
+
+// parseIf parses an {if} block starting at start. condPos is the position of
+// cond.
+func (p *bodyParser) parseIf(cond string, start, condPos Pos) (*ifNode, error) {
+	n := &ifNode{}
+	p.depth++
+	defer func() { p.depth-- }()
+	for {
+		body, end, err := p.parseNodes("")
+		if err != nil {
+			return nil, err
+		}
+		n.branches = append(n.branches, ifBranch{cond: cond, pos: condPos, body: body})
+		switch {
+		case end.kind == endEnd:
+			return n, nil
+		case end.kind == endElse && cond != "":
+			cond, start, condPos = end.cond, end.pos, end.condPos
+		default:
+			return nil, p.errorf(start, "{if} is not closed by {end}")
+		}
+	}
+}
+
+// splitKeyword splits the keyword of a control flow from the rest.
+func splitKeyword(code string) (string, string) {
+	code = strings.TrimSpace(code)
+	for _, kw := range []string{"if", "for", "else", "end"} {
+		if code == kw {
+			return kw, ""
+		}
+		if strings.HasPrefix(code, kw) && len(code) > len(kw) && (code[len(kw)] == ' ' || code[len(kw)] == '\t') {
+			return kw, strings.TrimSpace(code[len(kw):])
+		}
+	}
+	return "", code
+}
+
+// braced reads a {...} and returns the content. Braces in Go strings and
+// nested braces are skipped.
+func (p *bodyParser) braced() (string, error) {
+	start := p.curPos()
+	depth := 0
+	for i := p.pos; i < len(p.src); i++ {
+		switch c := p.src[i]; c {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				code := p.src[p.pos+1 : i]
+				p.advance(i + 1 - p.pos)
+				return code, nil
+			}
+		case '"', '\'', '`':
+			for i++; i < len(p.src) && p.src[i] != c; i++ {
+				if p.src[i] == '\\' && c != '`' {
+					i++
+				}
+			}
+		}
+	}
+	return "", p.errorf(start, "'{' is not closed")
+}
+
+func isNameStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func (p *bodyParser) name() string {
+	start := p.pos
+	n := 0
+	for p.pos+n < len(p.src) && strings.IndexByte(" \t\r\n\f/>=\"'{}<", p.src[p.pos+n]) < 0 {
+		n++
+	}
+	p.advance(n)
+	return p.src[start:p.pos]
+}
+
+func (p *bodyParser) skipSpaces() {
+	n := 0
+	for p.pos+n < len(p.src) && strings.IndexByte(" \t\r\n\f", p.src[p.pos+n]) >= 0 {
+		n++
+	}
+	p.advance(n)
+}
+
+func (p *bodyParser) parseElement() (*elemNode, error) {
+	el := &elemNode{pos: p.curPos()}
+	p.advance(1)
+	el.name = p.name()
+	foreign := strings.ToLower(el.name)
+	if foreign != el.name && !strings.Contains(el.name, ":") {
+		// HTML tag names are case-insensitive, but names of foreign elements,
+		// e.g. linearGradient, are kept.
+		if _, ok := tags.Types[foreign]; ok {
+			el.name = foreign
+		}
+	}
+
+	for {
+		p.skipSpaces()
+		if p.eof() {
+			return nil, p.errorf(el.pos, "<%s> is not closed", el.name)
+		}
+		if p.hasPrefix("/>") {
+			p.advance(2)
+			return el, nil
+		}
+		if p.hasPrefix(">") {
+			p.advance(1)
+			break
+		}
+		attr, err := p.parseAttr()
+		if err != nil {
+			return nil, err
+		}
+		el.attrs = append(el.attrs, attr)
+	}
+
+	if tags.Void[el.name] {
+		return el, nil
+	}
+	raw := ""
+	if rawTextTags[el.name] {
+		raw = el.name
+	}
+	p.depth++
+	children, end, err := p.parseNodes(raw)
+	p.depth--
+	if err != nil {
+		return nil, err
+	}
+	if end.kind != endTag || !strings.EqualFold(end.what, "</"+el.name+">") {
+		if end.kind == endEOF {
+			return nil, p.errorf(el.pos, "<%s> is not closed", el.name)
+		}
+		return nil, p.errorf(end.pos, "unexpected %s in <%s>", end.what, el.name)
+	}
+	el.children = children
+	return el, nil
+}
+
+func (p *bodyParser) parseAttr() (attrNode, error) {
+	pos := p.curPos()
+	a := attrNode{name: p.name()}
+	if a.name == "" {
+		return a, p.errorf(pos, "unexpected %q", p.src[p.pos])
+	}
+	p.skipSpaces()
+	if !p.hasPrefix("=") {
+		return a, nil
+	}
+	p.advance(1)
+	p.skipSpaces()
+	if p.eof() {
+		return a, p.errorf(pos, "value of %s is missing", a.name)
+	}
+
+	switch q := p.src[p.pos]; q {
+	case '{':
+		start := p.curPos()
+		code, err := p.braced()
+		if err != nil {
+			return a, err
+		}
+		code, pos := codeAt(code, start)
+		a.parts = append(a.parts, attrPart{text: code, isExpr: true, pos: pos})
+
+	case '"', '\'':
+		p.advance(1)
+		var lit strings.Builder
+		for {
+			if p.eof() {
+				return a, p.errorf(pos, "value of %s is not closed", a.name)
+			}
+			c := p.src[p.pos]
+			if c == q {
+				p.advance(1)
+				break
+			}
+			if c == '{' && !p.hasPrefix("{{") {
+				if lit.Len() > 0 {
+					a.parts = append(a.parts, attrPart{text: stdhtml.UnescapeString(lit.String())})
+					lit.Reset()
+				}
+				start := p.curPos()
+				code, err := p.braced()
+				if err != nil {
+					return a, err
+				}
+				code, pos := codeAt(code, start)
+				a.parts = append(a.parts, attrPart{text: code, isExpr: true, pos: pos})
+				continue
+			}
+			if c == '{' {
+				p.advance(1)
+			}
+			lit.WriteByte(p.src[p.pos])
+			p.advance(1)
+		}
+		if lit.Len() > 0 || len(a.parts) == 0 {
+			a.parts = append(a.parts, attrPart{text: stdhtml.UnescapeString(lit.String())})
+		}
+
+	default:
+		a.parts = append(a.parts, attrPart{text: stdhtml.UnescapeString(p.name())})
+	}
+	return a, nil
+}
@@ -0,0 +1,211 @@
+package html
+
+import (
+	. "github.com/gohtml/elements"
+)
+
+// The slots of the entries in a HeadSet. Entries are exported in the order of
+// their slots and, within a slot, in the order they were first put.
+type headSlot int
+
+const (
+	charsetSlot headSlot = iota
+	baseSlot
+	titleSlot
+	metaSlot
+	linkSlot
+	preloadSlot
+	styleSlot
+	scriptSlot
+
+	headSlotCount
+)
+
+type headEntry struct {
+	key  string
+	slot headSlot
+	node Node
+}
+
+// HeadSet manages the entries of a HEAD element.
+//
+// Entries put with the same key replace each other, so e.g. setting the title
+// twice results in a single TITLE element. Entries are exported in a correct
+// order regardless of the order they were put: the charset first, the base
+// before any URL-bearing elements, then the title, metas, links, preloads,
+// styles and scripts.
+//
+// Call Html.HeadSet to get the HeadSet of a document.
+type HeadSet struct {
+	entries []headEntry
+}
+
+var _ Node = (*HeadSet)(nil)
+
+// HeadContributor is implemented by nodes that need entries in the head of the
+// document they are in, e.g. a component requiring a stylesheet. When an Html
+// is rendered, ContributeHead is called for every such node in the tree. The
+// Html itself is not modified.
+type HeadContributor interface {
+	ContributeHead(hs *HeadSet)
+}
+
+func headSlotOf(nd Node) headSlot {
+	switch nd.Type() {
+	case BASETag:
+		return baseSlot
+
+	case TITLETag:
+		return titleSlot
+
+	case METATag:
+		if v := voidOf(nd); v != nil && v.attributes.index("charset") >= 0 {
+			return charsetSlot
+		}
+		return metaSlot
+
+	case LINKTag:
+		v := voidOf(nd)
+		if v == nil {
+			return linkSlot
+		}
+		rel, _ := v.attributes.get("rel")
+		switch rel {
+		case "stylesheet":
+			return styleSlot
+		case "preload", "modulepreload", "prefetch", "preconnect", "dns-prefetch":
+			return preloadSlot
+		}
+		return linkSlot
+
+	case STYLETag:
+		return styleSlot
+	}
+
+	return scriptSlot
+}
+
+// Put puts a node into the set. If an entry of the same key exists, it is
+// replaced. An empty key never matches any entry.
+//
+// The position of the node in the exported head is decided by its type, e.g.
+// a LINK with rel "stylesheet" is exported after all other LINKs.
+func (hs *HeadSet) Put(key string, nd Node) *HeadSet {
+	slot := headSlotOf(nd)
+	if key != "" {
+		for i := range hs.entries {
+			if hs.entries[i].key == key {
+				hs.entries[i].slot, hs.entries[i].node = slot, nd
+				return hs
+			}
+		}
+	}
+
+	hs.entries = append(hs.entries, headEntry{key: key, slot: slot, node: nd})
+	return hs
+}
+
+// Del deletes the entry with the specified key.
+func (hs *HeadSet) Del(key string) *HeadSet {
+	for i := range hs.entries {
+		if hs.entries[i].key == key {
+			hs.entries = append(hs.entries[:i], hs.entries[i+1:]...)
+			break
+		}
+	}
+	return hs
+}
+
+// Charset sets the charset META.
+func (hs *HeadSet) Charset(charset string) *HeadSet {
+	return hs.Put("charset", META().Attr("charset", charset))
+}
+
+// Title sets the TITLE.
+func (hs *HeadSet) Title(title string) *HeadSet {
+	return hs.Put("title", TITLE(title))
+}
+
+// Base sets the BASE.
+func (hs *HeadSet) Base(href URL, target string) *HeadSet {
+	return hs.Put("base", BASE(href, target))
+}
+
+// Meta sets a META with the name and content attributes. Metas are keyed by
+// names.
+func (hs *HeadSet) Meta(name, content string) *HeadSet {
+	return hs.Put("meta:name:"+name, META().Attr("name", name).Attr("content", content))
+}
+
+// MetaProperty sets a META with the property and content attributes, e.g. for
+// Open Graph. Metas are keyed by properties.
+func (hs *HeadSet) MetaProperty(property, content string) *HeadSet {
+	return hs.Put("meta:property:"+property, META().Attr("property", property).Attr("content", content))
+}
+
+// Link puts a LINK. Links are keyed by rel and href, except that a canonical
+// LINK replaces the one set by Canonical.
+func (hs *HeadSet) Link(href URL, rel string) *HeadSet {
+	if rel == "canonical" {
+		return hs.Canonical(href)
+	}
+	return hs.Put("link:"+rel+" "+string(href), LINK(href, rel))
+}
+
+// Canonical sets the canonical LINK.
+func (hs *HeadSet) Canonical(href URL) *HeadSet {
+	return hs.Put("canonical", LINK(href, "canonical"))
+}
+
+// Favicon sets the icon LINK. tp is the MIME type of the icon and is ignored if
+// empty.
+func (hs *HeadSet) Favicon(href URL, tp string) *HeadSet {
+	return hs.Put("icon", LINK(href, "shortcut icon").AttrIfNotEmpty("type", tp))
+}
+
+// Preload puts a preload LINK. as is the type of the content, e.g. "font".
+func (hs *HeadSet) Preload(href URL, as string) *HeadSet {
+	return hs.Put("link:preload "+string(href), LINK(href, "preload").AttrIfNotEmpty("as", as))
+}
+
+// Stylesheet puts a stylesheet LINK.
+func (hs *HeadSet) Stylesheet(href URL) *HeadSet {
+	return hs.Put("link:stylesheet "+string(href), LINK(href, "stylesheet").Attr("type", "text/css"))
+}
+
+// Script puts an external SCRIPT. Scripts are keyed by src.
+func (hs *HeadSet) Script(src URL) *HeadSet {
+	return hs.Put("script:"+string(src), SCRIPT(src, ""))
+}
+
+func (hs *HeadSet) clone() *HeadSet {
+	return &HeadSet{
+		entries: append([]headEntry(nil), hs.entries...),
+	}
+}
+
+// Implementation of Node interface
+func (hs *HeadSet) Type() TagType {
+	// The charset META is the first entry in most cases.
+	return METATag
+}
+
+// Children returns the nodes of the entries in the order they are exported.
+func (hs *HeadSet) Children() []Node {
+	var nodes []Node
+	for slot := headSlot(0); slot < headSlotCount; slot++ {
+		for i := range hs.entries {
+			if hs.entries[i].slot == slot {
+				nodes = append(nodes, hs.entries[i].node)
+			}
+		}
+	}
+	return nodes
+}
+
+// Implementation of Node interface
+func (hs *HeadSet) WriteTo(b Writer, opt RenderOptions, parent *Element, childIndex int) {
+	for _, nd := range hs.Children() {
+		nd.WriteTo(b, opt, parent, childIndex)
+	}
+}
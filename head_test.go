@@ -0,0 +1,112 @@
+package html
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golangplus/testing/assert"
+
+	. "github.com/gohtml/elements"
+)
+
+func TestHeadSet_dedup(t *testing.T) {
+	h := HTML("")
+	h.Title("First")
+	h.Title("Second")
+	h.Css("main.css")
+	h.Css("main.css")
+
+	assert.StringEqual(t, "html", NodeToHTMLNode(h, RenderOptions{SortAttr: true}),
+		`<!DOCTYPE html>
+<meta charset="utf-8"><title>Second</title><link href="main.css" rel="stylesheet" type="text/css">`)
+}
+
+func TestHeadSet_order(t *testing.T) {
+	h := HTML("")
+	hs := h.HeadSet()
+	hs.Script("app.js")
+	hs.Stylesheet("main.css")
+	hs.Preload("font.woff2", "font")
+	hs.Canonical("http://example.com/")
+	hs.Meta("description", "Desc")
+	h.Title("Title")
+	h.Base("/sub/", "")
+	hs.Charset("latin1")
+
+	assert.StringEqual(t, "html", NodeToHTMLNode(h, RenderOptions{SortAttr: true}),
+		`<!DOCTYPE html>
+<meta charset="latin1"><base href="/sub/"><title>Title</title><meta content="Desc" name="description">`+
+			`<link href="http://example.com/" rel="canonical"><link as="font" href="font.woff2" rel="preload">`+
+			`<link href="main.css" rel="stylesheet" type="text/css"><script src="app.js"></script>`)
+
+	hs.Del("canonical")
+	assert.StringEqual(t, "html", NodeToHTMLNode(h, RenderOptions{SortAttr: true}),
+		`<!DOCTYPE html>
+<meta charset="latin1"><base href="/sub/"><title>Title</title><meta content="Desc" name="description">`+
+			`<link as="font" href="font.woff2" rel="preload">`+
+			`<link href="main.css" rel="stylesheet" type="text/css"><script src="app.js"></script>`)
+}
+
+func TestHeadSet_canonicalLink(t *testing.T) {
+	hs := &HeadSet{}
+	hs.Canonical("http://example.com/a")
+	hs.Link("http://example.com/b", "canonical")
+	assert.StringEqual(t, "html", NodeToHTMLNode(hs, RenderOptions{}),
+		`<link href="http://example.com/b" rel="canonical">`)
+	hs.Del("canonical")
+	assert.Equal(t, "len(hs.entries)", len(hs.entries), 0)
+}
+
+func TestHeadSet_children(t *testing.T) {
+	h := HTML("")
+	h.HeadSet().Stylesheet("main.css").Put("widget", &widget{Element: Element{Void: Void{tagType: SPANTag}}})
+
+	var found []TagType
+	Walk(h, func(nd Node) bool {
+		found = append(found, nd.Type())
+		return true
+	})
+	// The HeadSet itself is of METATag.
+	assert.StringEqual(t, "found", found, []TagType{HTMLTag, HEADTag, METATag, METATag, LINKTag, SPANTag, BODYTag})
+
+	exp := `<!DOCTYPE html>
+<meta charset="utf-8"><title>Widget</title><link href="main.css" rel="stylesheet" type="text/css">` +
+		`<link href="widget.css" rel="stylesheet" type="text/css"><span></span>`
+	// The widget in the HeadSet contributes to the head.
+	assert.StringEqual(t, "html", NodeToHTMLNode(h, RenderOptions{SortAttr: true}), exp)
+	assert.Equal(t, "ChildNodes", len(ChildNodes(h.HeadSet())), 3)
+}
+
+type widget struct {
+	Element
+}
+
+func (w *widget) ContributeHead(hs *HeadSet) {
+	hs.Stylesheet("widget.css")
+	hs.Title("Widget")
+}
+
+func TestHeadContributor(t *testing.T) {
+	h := HTML("")
+	h.Title("Page")
+	h.Body().Child(DIV(&widget{Element: Element{Void: Void{tagType: SPANTag}}}))
+
+	exp := `<!DOCTYPE html>
+<meta charset="utf-8"><title>Widget</title><link href="widget.css" rel="stylesheet" type="text/css"><div><span></span></div>`
+	assert.StringEqual(t, "html", NodeToHTMLNode(h, RenderOptions{SortAttr: true}), exp)
+	// Contributions must not be kept in the Html.
+	assert.Equal(t, "len(h.head.entries)", len(h.head.entries), 2)
+	assert.StringEqual(t, "html", NodeToHTMLNode(h, RenderOptions{SortAttr: true}), exp)
+}
+
+func ExampleHeadSet() {
+	h := HTML("en")
+	h.Css("main.css")
+	h.Base("/app/", "")
+	h.Title("Draft")
+	h.Title("Home")
+	fmt.Println(NodeToHTMLNode(h, RenderOptions{SortAttr: true}))
+	// OUTPUT:
+	// <!DOCTYPE html>
+	// <html lang="en"><meta charset="utf-8"><base href="/app/"><title>Home</title><link href="main.css" rel="stylesheet" type="text/css">
+}
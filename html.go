@@ -12,6 +12,10 @@ var (
 // Do not create Html directly, call HTML function instead.
 type Html struct {
 	Element
+	head HeadSet
+
+	styles       *StyleSet
+	stylesPrefix URL
 }
 
 var _ Node = (*Html)(nil)
@@ -21,7 +25,55 @@ func (h *Html) WriteTo(b Writer, opt RenderOptions, parent *Element, childIndex
 	b.WriteString(doctypeNode)
 	b.WriteByte('\n')
 
-	h.Element.WriteTo(b, opt, parent, childIndex)
+	h.rendered(&opt).WriteTo(b, opt, parent, childIndex)
+}
+
+// rendered returns the Element to render. If any node in the tree is a
+// HeadContributor, uses a ScopedStyle of the StyleSet of the Html, or is a
+// module SCRIPT of an asset of opt.Assets, a shallow copy with the
+// contributions merged into the HeadSet is returned so that the Html itself is
+// never modified.
+func (h *Html) rendered(opt *RenderOptions) *Element {
+	var (
+		hs   *HeadSet
+		used map[*ScopedStyle]bool
+	)
+	merged := func() *HeadSet {
+		if hs == nil {
+			hs = h.head.clone()
+		}
+		return hs
+	}
+	Walk(&h.Element, func(nd Node) bool {
+		if c, ok := nd.(HeadContributor); ok {
+			c.ContributeHead(merged())
+		}
+		if h.styles != nil {
+			used = h.styles.collect(nd, used)
+		}
+		if opt.Assets != nil {
+			opt.Assets.contributeHead(nd, merged)
+		}
+		return true
+	})
+	if len(used) > 0 {
+		merged().Put("scoped-styles", h.styles.node(used, h.stylesPrefix))
+	}
+	if hs == nil {
+		return &h.Element
+	}
+
+	head := *h.Head()
+	head.children = append([]Node(nil), head.children...)
+	for i, child := range head.children {
+		if child == Node(&h.head) {
+			head.children[i] = hs
+		}
+	}
+
+	el := h.Element
+	el.children = append([]Node{&head}, h.children[1:]...)
+	return &el
 }
 
 // Implementation of Node interface
@@ -42,29 +94,37 @@ func (h *Html) Body() *Element {
 	return h.children[1].(*Element)
 }
 
+// HeadSet returns the HeadSet managing the entries of the HEAD element.
+// Nodes appended to Head() directly are exported after the entries.
+func (h *Html) HeadSet() *HeadSet {
+	return &h.head
+}
+
 func (h *Html) Lang(lang string) *Html {
 	h.NonEmptyAttr("lang", lang)
 	return h
 }
 
+// Title sets the title of the document. Calling it again replaces the title.
 func (h *Html) Title(title string) *Html {
-	h.Head().Child(TITLE(title))
+	h.head.Title(title)
 	return h
 }
 
+// Base sets the base of the document. The BASE is always exported before any
+// elements with URL attributes in the head.
 func (h *Html) Base(href URL, target string) *Html {
-	// FIXME should make sure base comes before any other elements have URL attributes.
-	h.Head().Child(BASE(href, target))
+	h.head.Base(href, target)
 	return h
 }
 
 func (h *Html) Favicon(href URL, tp string) *Html {
-	h.Head().Child(LINK(href, "shortcut icon").Attr("type", tp))
+	h.head.Favicon(href, tp)
 	return h
 }
 
 func (h *Html) Css(href URL) *Html {
-	h.Head().Child(LINK(href, "stylesheet").Attr("type", "text/css"))
+	h.head.Stylesheet(href)
 	return h
 }
 
@@ -73,6 +133,15 @@ func (h *Html) SCRIPT(src URL, content string) *Html {
 	return h
 }
 
+// UseStyles makes the document include the CSS of the ScopedStyles of set
+// which are used in the tree. If prefix is empty, the CSS is inlined in a STYLE
+// in the head. Otherwise a stylesheet LINK to prefix + a file name containing
+// the hashes of the styles is added, and set should be served at prefix.
+func (h *Html) UseStyles(set *StyleSet, prefix URL) *Html {
+	h.styles, h.stylesPrefix = set, prefix
+	return h
+}
+
 // Manifest sets the "manifest" attribute of the HTML node.
 func (h *Html) Manifest(src URL) *Html {
 	h.NonEmptyAttr("manifest", string(src))
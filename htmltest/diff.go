@@ -0,0 +1,160 @@
+package htmltest
+
+import (
+	"sort"
+	"strings"
+)
+
+// The number of unchanged lines shown around changes.
+const diffContext = 3
+
+type diffLine struct {
+	op   byte
+	text string
+}
+
+// diffLines appends the diff from a to b to lines. It uses the linear space
+// variation of the Myers algorithm after trimming the common prefix and
+// suffix.
+func diffLines(lines []diffLine, a, b []string) []diffLine {
+	for len(a) > 0 && len(b) > 0 && a[0] == b[0] {
+		lines = append(lines, diffLine{' ', a[0]})
+		a, b = a[1:], b[1:]
+	}
+	suffix := 0
+	for suffix < len(a) && suffix < len(b) && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	common := a[len(a)-suffix:]
+	a, b = a[:len(a)-suffix], b[:len(b)-suffix]
+
+	switch {
+	case len(a) == 0:
+		for _, l := range b {
+			lines = append(lines, diffLine{'+', l})
+		}
+	case len(b) == 0:
+		for _, l := range a {
+			lines = append(lines, diffLine{'-', l})
+		}
+	default:
+		x, y, u, v := middleSnake(a, b)
+		lines = diffLines(lines, a[:x], b[:y])
+		for _, l := range a[x:u] {
+			lines = append(lines, diffLine{' ', l})
+		}
+		lines = diffLines(lines, a[u:], b[v:])
+	}
+
+	for _, l := range common {
+		lines = append(lines, diffLine{' ', l})
+	}
+	return lines
+}
+
+// middleSnake returns the middle snake, from (x, y) to (u, v), of a shortest
+// edit script from a to b, searching forward from the start and backward from
+// the end at the same time.
+func middleSnake(a, b []string) (x, y, u, v int) {
+	n, m := len(a), len(b)
+	delta := n - m
+	odd := delta%2 != 0
+	max := (n + m + 1) / 2
+	// The furthest x on each diagonal k, at k+off. Backward x is counted from
+	// the ends.
+	off := max + 1
+	vf := make([]int, 2*off+1)
+	vb := make([]int, 2*off+1)
+	for d := 0; d <= max; d++ {
+		for k := -d; k <= d; k += 2 {
+			if k == -d || k != d && vf[k-1+off] < vf[k+1+off] {
+				x = vf[k+1+off]
+			} else {
+				x = vf[k-1+off] + 1
+			}
+			y = x - k
+			u, v = x, y
+			for u < n && v < m && a[u] == b[v] {
+				u, v = u+1, v+1
+			}
+			vf[k+off] = u
+			if kb := delta - k; odd && kb >= -(d-1) && kb <= d-1 && u+vb[kb+off] >= n {
+				return x, y, u, v
+			}
+		}
+		for k := -d; k <= d; k += 2 {
+			var bx int
+			if k == -d || k != d && vb[k-1+off] < vb[k+1+off] {
+				bx = vb[k+1+off]
+			} else {
+				bx = vb[k-1+off] + 1
+			}
+			by := bx - k
+			ex, ey := bx, by
+			for ex < n && ey < m && a[n-1-ex] == b[m-1-ey] {
+				ex, ey = ex+1, ey+1
+			}
+			vb[k+off] = ex
+			if kf := delta - k; !odd && kf >= -d && kf <= d && ex+vf[kf+off] >= n {
+				return n - ex, m - ey, n - bx, m - by
+			}
+		}
+	}
+	// Unreachable since there is always a path within max.
+	return 0, 0, 0, 0
+}
+
+// Diff returns a line diff from want to got, or an empty string if they are the
+// same. Removed lines are prefixed with "-", added lines with "+" and
+// unchanged lines around the changes with a space. Omitted unchanged lines are
+// shown as "...".
+func Diff(want, got string) string {
+	if want == got {
+		return ""
+	}
+	a := strings.Split(strings.TrimSuffix(want, "\n"), "\n")
+	b := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+	lines := diffLines(nil, a, b)
+	// Within each change, removed lines go before added lines.
+	for i := 0; i < len(lines); {
+		if lines[i].op == ' ' {
+			i++
+			continue
+		}
+		j := i
+		for j < len(lines) && lines[j].op != ' ' {
+			j++
+		}
+		sort.SliceStable(lines[i:j], func(x, y int) bool {
+			return lines[i+x].op == '-' && lines[i+y].op == '+'
+		})
+		i = j
+	}
+
+	// Only unchanged lines close to changes are shown.
+	show := make([]bool, len(lines))
+	for k, l := range lines {
+		if l.op == ' ' {
+			continue
+		}
+		for d := k - diffContext; d <= k+diffContext; d++ {
+			if d >= 0 && d < len(lines) {
+				show[d] = true
+			}
+		}
+	}
+
+	var res strings.Builder
+	for k, l := range lines {
+		if !show[k] {
+			if k == 0 || show[k-1] {
+				res.WriteString("...\n")
+			}
+			continue
+		}
+		res.WriteByte(l.op)
+		res.WriteString(l.text)
+		res.WriteByte('\n')
+	}
+	return res.String()
+}
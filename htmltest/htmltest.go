@@ -0,0 +1,281 @@
+// Package htmltest provides helpers for testing rendered html.Node trees.
+//
+// Instead of comparing rendered strings, which break whenever the order of
+// attributes or the omission of optional tags changes, trees are compared as
+// DOMs: attributes are sorted by names, all end tags are explicit and
+// whitespace in texts is collapsed. Differences are reported as diffs of the
+// indented trees.
+//
+// Golden files are read from the testdata directory of the package under
+// test. Run the tests with the -update flag, or with the environment variable
+// HTMLTEST_UPDATE set, to rewrite them:
+//
+//	go test -update
+package htmltest
+
+import (
+	"flag"
+	stdhtml "html"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	. "github.com/gohtml/elements"
+	"github.com/gohtml/html"
+)
+
+// UpdateEnv is the environment variable making AssertGolden write the golden
+// files instead of comparing them if it is not empty.
+const UpdateEnv = "HTMLTEST_UPDATE"
+
+func init() {
+	// The package under test may define its own -update flag, which is then
+	// shared.
+	if flag.Lookup("update") == nil {
+		flag.Bool("update", false, "update the golden files of htmltest")
+	}
+}
+
+// updating returns whether golden files are written instead of compared.
+func updating() bool {
+	if f := flag.Lookup("update"); f != nil && f.Value.String() == "true" {
+		return true
+	}
+	return os.Getenv(UpdateEnv) != ""
+}
+
+var attrEscaper = strings.NewReplacer(`&`, "&amp;", `"`, "&quot;")
+
+type namedNode interface {
+	Name() html.HTMLNode
+}
+
+type attrNode interface {
+	EachAttr(fn func(name, value string))
+}
+
+// collapseSpaces replaces runs of whitespace in s with single spaces and trims
+// the spaces at both ends.
+func collapseSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+type printer struct {
+	lines []string
+}
+
+func (p *printer) line(depth int, s string) {
+	p.lines = append(p.lines, strings.Repeat("  ", depth)+s)
+}
+
+func (p *printer) node(nd html.Node, depth int) {
+	if nd.Type() == TextType {
+		if txt := collapseSpaces(string(html.NodeToHTMLNode(nd, html.DefaultOptions))); txt != "" {
+			p.line(depth, txt)
+		}
+		return
+	}
+
+	named, ok := nd.(namedNode)
+	if !ok {
+		// Unknown nodes are rendered.
+		p.line(depth, collapseSpaces(string(html.NodeToHTMLNode(nd, html.RenderOptions{
+			DisableOmit: true,
+			AttrOrder:   html.AlphabeticalOrder,
+		}))))
+		return
+	}
+	name := string(named.Name())
+
+	var b strings.Builder
+	b.WriteString("<" + name)
+	if an, ok := nd.(attrNode); ok {
+		var attrs []string
+		an.EachAttr(func(name, value string) {
+			if value == "" {
+				attrs = append(attrs, name)
+			} else {
+				attrs = append(attrs, name+`="`+attrEscaper.Replace(value)+`"`)
+			}
+		})
+		sort.Strings(attrs)
+		for _, attr := range attrs {
+			b.WriteString(" " + attr)
+		}
+	}
+	b.WriteString(">")
+
+	if _, ok := nd.(interface{ Children() []html.Node }); !ok {
+		// A void element.
+		p.line(depth, b.String())
+		return
+	}
+
+	children := html.ChildNodes(nd)
+	if len(children) == 0 {
+		p.line(depth, b.String()+"</"+name+">")
+		return
+	}
+	p.line(depth, b.String())
+	// Adjacent texts are merged.
+	var txt []html.Node
+	flush := func() {
+		if len(txt) > 0 {
+			var s strings.Builder
+			for _, t := range txt {
+				s.WriteString(string(html.NodeToHTMLNode(t, html.DefaultOptions)))
+			}
+			p.node(html.HTMLNode(s.String()), depth+1)
+			txt = txt[:0]
+		}
+	}
+	for _, child := range children {
+		if child.Type() == TextType {
+			txt = append(txt, child)
+			continue
+		}
+		flush()
+		p.node(child, depth+1)
+	}
+	flush()
+	p.line(depth, "</"+name+">")
+}
+
+// Sprint formats the tree rooted at nd as indented HTML with one tag or text
+// per line. Attributes are sorted by names, all end tags are explicit and
+// whitespace in texts is collapsed. Two trees are DOM-equivalent if and only
+// if their Sprint results are the same.
+func Sprint(nd html.Node) string {
+	var p printer
+	if _, ok := nd.(*html.Html); ok {
+		p.line(0, "<!DOCTYPE html>")
+	}
+	p.node(nd, 0)
+	return strings.Join(p.lines, "\n") + "\n"
+}
+
+// Equal returns true if the two trees are DOM-equivalent.
+func Equal(a, b html.Node) bool {
+	return Sprint(a) == Sprint(b)
+}
+
+// Text returns the text content of nd with whitespace collapsed.
+func Text(nd html.Node) string {
+	var b strings.Builder
+	var visit func(nd html.Node)
+	visit = func(nd html.Node) {
+		if nd.Type() == TextType {
+			b.WriteString(stdhtml.UnescapeString(string(html.NodeToHTMLNode(nd, html.DefaultOptions))))
+			return
+		}
+		for _, child := range html.ChildNodes(nd) {
+			visit(child)
+		}
+	}
+	visit(nd)
+	return collapseSpaces(b.String())
+}
+
+// AssertEqual reports an error with the diff if got is not DOM-equivalent to
+// want.
+func AssertEqual(t testing.TB, got, want html.Node) {
+	t.Helper()
+	if diff := Diff(Sprint(want), Sprint(got)); diff != "" {
+		t.Errorf("DOMs differ (-want +got):\n%s", diff)
+	}
+}
+
+// AssertGolden compares got with the golden file testdata/<name>.golden, which
+// contains the Sprint result of the expected tree. If the -update flag or the
+// environment variable UpdateEnv is set, the golden file is written instead.
+func AssertGolden(t testing.TB, got html.Node, name string) {
+	t.Helper()
+	fn := filepath.Join("testdata", name+".golden")
+	actual := Sprint(got)
+	if updating() {
+		if err := os.MkdirAll(filepath.Dir(fn), 0755); err != nil {
+			t.Fatalf("AssertGolden: %v", err)
+			return
+		}
+		if err := ioutil.WriteFile(fn, []byte(actual), 0644); err != nil {
+			t.Fatalf("AssertGolden: %v", err)
+		}
+		return
+	}
+
+	expected, err := ioutil.ReadFile(fn)
+	if err != nil {
+		t.Fatalf("AssertGolden: %v (run the test with -update or %s=1 to create it)", err, UpdateEnv)
+		return
+	}
+	if diff := Diff(string(expected), actual); diff != "" {
+		t.Errorf("%s differs (-want +got):\n%s", fn, diff)
+	}
+}
+
+func selectNodes(t testing.TB, root html.Node, selector string) ([]html.Node, bool) {
+	t.Helper()
+	sel, err := html.ParseSelector(selector)
+	if err != nil {
+		t.Fatalf("%v", err)
+		return nil, false
+	}
+	return sel.Select(root), true
+}
+
+// AssertCount reports an error if the number of nodes matching the selector is
+// not n.
+func AssertCount(t testing.TB, root html.Node, selector string, n int) {
+	t.Helper()
+	nodes, ok := selectNodes(t, root, selector)
+	if ok && len(nodes) != n {
+		t.Errorf("%q: got %d nodes, want %d", selector, len(nodes), n)
+	}
+}
+
+// AssertText reports an error if no node matches the selector, or the Text of
+// the first matching node is not want.
+func AssertText(t testing.TB, root html.Node, selector, want string) {
+	t.Helper()
+	nodes, ok := selectNodes(t, root, selector)
+	if !ok {
+		return
+	}
+	if len(nodes) == 0 {
+		t.Errorf("%q: no node matches", selector)
+		return
+	}
+	if got := Text(nodes[0]); got != want {
+		t.Errorf("%q: got text %q, want %q", selector, got, want)
+	}
+}
+
+// AssertAttr reports an error if no node matches the selector, or the first
+// matching node has no attribute of the name or its value is not want.
+func AssertAttr(t testing.TB, root html.Node, selector, name, want string) {
+	t.Helper()
+	nodes, ok := selectNodes(t, root, selector)
+	if !ok {
+		return
+	}
+	if len(nodes) == 0 {
+		t.Errorf("%q: no node matches", selector)
+		return
+	}
+	an, ok := nodes[0].(interface {
+		GetAttr(name string) (string, bool)
+	})
+	if !ok {
+		t.Errorf("%q: the node has no attributes", selector)
+		return
+	}
+	got, ok := an.GetAttr(name)
+	if !ok {
+		t.Errorf("%q: no attribute %q, want %q", selector, name, want)
+	} else if got != want {
+		t.Errorf("%q: got %s=%q, want %q", selector, name, got, want)
+	}
+}
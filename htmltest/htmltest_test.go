@@ -0,0 +1,154 @@
+package htmltest
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golangplus/testing/assert"
+
+	"github.com/gohtml/html"
+)
+
+// fakeT records the errors instead of failing the test.
+type fakeT struct {
+	testing.TB
+	errs []string
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Errorf(format string, args ...interface{}) {
+	t.errs = append(t.errs, fmt.Sprintf(format, args...))
+}
+
+func (t *fakeT) Fatalf(format string, args ...interface{}) {
+	t.Errorf(format, args...)
+}
+
+func menu() *html.Element {
+	return html.NAV(html.UL(
+		html.LI(html.A("/", html.T("Home"))).AddClass("active"),
+		html.LI(html.A("/about", html.T("About"), html.T(" us"))),
+	)).Attr("id", "menu").Attr("aria-label", "Main")
+}
+
+func TestSprint(t *testing.T) {
+	assert.Equal(t, "Sprint", Sprint(menu()), `<nav aria-label="Main" id="menu">
+  <ul>
+    <li class="active">
+      <a href="/">
+        Home
+      </a>
+    </li>
+    <li>
+      <a href="/about">
+        About us
+      </a>
+    </li>
+  </ul>
+</nav>
+`)
+}
+
+func TestEqual(t *testing.T) {
+	a := html.DIV(html.P(html.T("a  b")), html.INPUT("text", "q", "")).Attr("id", "x").AddClass("c")
+	b := html.DIV(html.P(html.T("a"), html.T(" b\n"))).AddClass("c").Attr("id", "x")
+	b.Child(html.INPUT("text", "q", ""))
+	assert.True(t, "equal", Equal(a, b))
+	assert.False(t, "not equal", Equal(a, html.DIV()))
+}
+
+func TestDiff(t *testing.T) {
+	assert.Equal(t, "same", Diff("a\nb\n", "a\nb\n"), "")
+	assert.Equal(t, "diff", Diff("1\n2\n3\n4\n5\n6\n7\n8\n9\n", "1\n2\n3\n4\n5\nX\n7\n8\n9\n"),
+		"...\n 3\n 4\n 5\n-6\n+X\n 7\n 8\n 9\n")
+	assert.Equal(t, "insert and delete", Diff("a\nb\nc\nd\n", "b\nc\nx\nd\ny\n"),
+		"-a\n b\n c\n+x\n d\n+y\n")
+	assert.Equal(t, "all changed", Diff("a\nb\n", "c\n"), "-a\n-b\n+c\n")
+	assert.Equal(t, "empty", Diff("", "a\n"), "-\n+a\n")
+}
+
+func TestAssertEqual(t *testing.T) {
+	ft := &fakeT{}
+	AssertEqual(ft, html.DIV(html.SPAN(html.T("b"))), html.DIV(html.SPAN(html.T("a"))))
+	assert.StringEqual(t, "errs", ft.errs, []string{"DOMs differ (-want +got):\n <div>\n   <span>\n-    a\n+    b\n   </span>\n </div>\n"})
+}
+
+func TestAssertGolden(t *testing.T) {
+	AssertGolden(t, menu(), "menu")
+
+	ft := &fakeT{}
+	AssertGolden(ft, html.DIV(), "menu")
+	assert.Equal(t, "len(errs)", len(ft.errs), 1)
+
+	ft = &fakeT{}
+	AssertGolden(ft, html.DIV(), "missing")
+	assert.True(t, "missing", len(ft.errs) == 1 && strings.Contains(ft.errs[0], UpdateEnv+"=1"))
+}
+
+func TestAssertGolden_update(t *testing.T) {
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+	assert.NoError(t, os.Chdir(t.TempDir()))
+	defer os.Chdir(wd)
+
+	t.Setenv(UpdateEnv, "1")
+	AssertGolden(t, menu(), "menu")
+	golden, err := ioutil.ReadFile(filepath.Join("testdata", "menu.golden"))
+	assert.NoError(t, err)
+	assert.Equal(t, "golden", string(golden), Sprint(menu()))
+}
+
+func TestAssertGolden_updateFlag(t *testing.T) {
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+	assert.NoError(t, os.Chdir(t.TempDir()))
+	defer os.Chdir(wd)
+
+	assert.NoError(t, flag.Set("update", "true"))
+	defer flag.Set("update", "false")
+	AssertGolden(t, menu(), "menu")
+	golden, err := ioutil.ReadFile(filepath.Join("testdata", "menu.golden"))
+	assert.NoError(t, err)
+	assert.Equal(t, "golden", string(golden), Sprint(menu()))
+}
+
+func TestSelectorAssertions(t *testing.T) {
+	AssertText(t, menu(), "nav li.active", "Home")
+	AssertCount(t, menu(), "li > a", 2)
+	AssertAttr(t, menu(), "li:last-child a", "href", "/about")
+
+	ft := &fakeT{}
+	AssertText(ft, menu(), "li a", "About")
+	AssertCount(ft, menu(), "li", 3)
+	AssertAttr(ft, menu(), "ul", "id", "")
+	AssertText(ft, menu(), "li[", "")
+	assert.Equal(t, "len(errs)", len(ft.errs), 4)
+}
+
+func ExampleSprint() {
+	h := html.HTML("en")
+	h.Title("Home")
+	h.Body().Child(html.P(html.T("Hello")))
+	fmt.Print(Sprint(h))
+	// OUTPUT:
+	// <!DOCTYPE html>
+	// <html lang="en">
+	//   <head>
+	//     <meta charset="utf-8">
+	//     <title>
+	//       Home
+	//     </title>
+	//   </head>
+	//   <body>
+	//     <p>
+	//       Hello
+	//     </p>
+	//   </body>
+	// </html>
+}
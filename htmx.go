@@ -0,0 +1,239 @@
+package html
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/gohtml/elements"
+)
+
+// HxSwap is a value of the hx-swap attribute of htmx.
+// https://htmx.org/attributes/hx-swap/
+type HxSwap string
+
+const (
+	SwapInnerHTML   HxSwap = "innerHTML"
+	SwapOuterHTML   HxSwap = "outerHTML"
+	SwapBeforeBegin HxSwap = "beforebegin"
+	SwapAfterBegin  HxSwap = "afterbegin"
+	SwapBeforeEnd   HxSwap = "beforeend"
+	SwapAfterEnd    HxSwap = "afterend"
+	SwapDelete      HxSwap = "delete"
+	SwapNone        HxSwap = "none"
+)
+
+// HxTrigger is a trigger of the hx-trigger attribute of htmx, created by Trigger.
+// https://htmx.org/attributes/hx-trigger/
+type HxTrigger struct {
+	event     string
+	filter    string
+	modifiers []string
+}
+
+// Trigger creates an HxTrigger of an event, e.g. "click", "keyup" or "load".
+func Trigger(event string) *HxTrigger {
+	return &HxTrigger{event: event}
+}
+
+// htmxDuration formats d as a time interval of htmx.
+func htmxDuration(d time.Duration) string {
+	if d%time.Second == 0 {
+		return strconv.FormatInt(int64(d/time.Second), 10) + "s"
+	}
+	return strconv.FormatInt(int64(d/time.Millisecond), 10) + "ms"
+}
+
+// Filter sets a JavaScript expression the event must satisfy, e.g. "ctrlKey".
+func (t *HxTrigger) Filter(expr string) *HxTrigger {
+	t.filter = expr
+	return t
+}
+
+// Once makes the trigger fire only once.
+func (t *HxTrigger) Once() *HxTrigger {
+	t.modifiers = append(t.modifiers, "once")
+	return t
+}
+
+// Changed makes the trigger fire only if the value of the element has changed.
+func (t *HxTrigger) Changed() *HxTrigger {
+	t.modifiers = append(t.modifiers, "changed")
+	return t
+}
+
+// Delay delays the request, and restarts the delay if the event fires again.
+func (t *HxTrigger) Delay(d time.Duration) *HxTrigger {
+	t.modifiers = append(t.modifiers, "delay:"+htmxDuration(d))
+	return t
+}
+
+// Throttle ignores the events within d after a request.
+func (t *HxTrigger) Throttle(d time.Duration) *HxTrigger {
+	t.modifiers = append(t.modifiers, "throttle:"+htmxDuration(d))
+	return t
+}
+
+// From listens to the event on other elements, e.g. "document" or "closest form".
+func (t *HxTrigger) From(selector string) *HxTrigger {
+	t.modifiers = append(t.modifiers, "from:"+selector)
+	return t
+}
+
+// Every creates a polling trigger. The event of t is ignored.
+func (t *HxTrigger) Every(d time.Duration) *HxTrigger {
+	t.event = "every " + htmxDuration(d)
+	return t
+}
+
+func (t *HxTrigger) String() string {
+	s := t.event
+	if t.filter != "" {
+		s += "[" + t.filter + "]"
+	}
+	if len(t.modifiers) > 0 {
+		s += " " + strings.Join(t.modifiers, " ")
+	}
+	return s
+}
+
+// HxGet sets the hx-get attribute.
+func (v *Void) HxGet(url URL) *Void {
+	return v.Attr("hx-get", string(url))
+}
+
+// HxPost sets the hx-post attribute.
+func (v *Void) HxPost(url URL) *Void {
+	return v.Attr("hx-post", string(url))
+}
+
+// HxPut sets the hx-put attribute.
+func (v *Void) HxPut(url URL) *Void {
+	return v.Attr("hx-put", string(url))
+}
+
+// HxPatch sets the hx-patch attribute.
+func (v *Void) HxPatch(url URL) *Void {
+	return v.Attr("hx-patch", string(url))
+}
+
+// HxDelete sets the hx-delete attribute.
+func (v *Void) HxDelete(url URL) *Void {
+	return v.Attr("hx-delete", string(url))
+}
+
+// HxTarget sets the hx-target attribute, e.g. "#result" or "closest tr".
+func (v *Void) HxTarget(selector string) *Void {
+	return v.Attr("hx-target", selector)
+}
+
+// HxSwap sets the hx-swap attribute. Modifiers, e.g. "scroll:top", are
+// appended to the swap style.
+func (v *Void) HxSwap(swap HxSwap, modifiers ...string) *Void {
+	return v.Attr("hx-swap", strings.Join(append([]string{string(swap)}, modifiers...), " "))
+}
+
+// HxTrigger sets the hx-trigger attribute.
+func (v *Void) HxTrigger(triggers ...*HxTrigger) *Void {
+	strs := make([]string, len(triggers))
+	for i, t := range triggers {
+		strs[i] = t.String()
+	}
+	return v.Attr("hx-trigger", strings.Join(strs, ", "))
+}
+
+// HxGet is same as Void.HxGet but returns a *Element.
+func (e *Element) HxGet(url URL) *Element {
+	e.Void.HxGet(url)
+	return e
+}
+
+// HxPost is same as Void.HxPost but returns a *Element.
+func (e *Element) HxPost(url URL) *Element {
+	e.Void.HxPost(url)
+	return e
+}
+
+// HxPut is same as Void.HxPut but returns a *Element.
+func (e *Element) HxPut(url URL) *Element {
+	e.Void.HxPut(url)
+	return e
+}
+
+// HxPatch is same as Void.HxPatch but returns a *Element.
+func (e *Element) HxPatch(url URL) *Element {
+	e.Void.HxPatch(url)
+	return e
+}
+
+// HxDelete is same as Void.HxDelete but returns a *Element.
+func (e *Element) HxDelete(url URL) *Element {
+	e.Void.HxDelete(url)
+	return e
+}
+
+// HxTarget is same as Void.HxTarget but returns a *Element.
+func (e *Element) HxTarget(selector string) *Element {
+	e.Void.HxTarget(selector)
+	return e
+}
+
+// HxSwap is same as Void.HxSwap but returns a *Element.
+func (e *Element) HxSwap(swap HxSwap, modifiers ...string) *Element {
+	e.Void.HxSwap(swap, modifiers...)
+	return e
+}
+
+// HxTrigger is same as Void.HxTrigger but returns a *Element.
+func (e *Element) HxTrigger(triggers ...*HxTrigger) *Element {
+	e.Void.HxTrigger(triggers...)
+	return e
+}
+
+// idSelector returns the Selector of "#" + id. Unlike ParseSelector, id may
+// contain any characters.
+func idSelector(id string) *Selector {
+	return &Selector{
+		src:  "#" + id,
+		alts: [][]compoundSelector{{{attrs: []attrSelector{{name: "id", op: "=", value: id}}}}},
+	}
+}
+
+// contentOf renders the children of an element.
+type contentOf struct {
+	nd Node
+}
+
+func (c contentOf) Type() TagType {
+	return c.nd.Type()
+}
+
+func (c contentOf) WriteTo(b Writer, opt RenderOptions, parent *Element, childIndex int) {
+	el, _ := c.nd.(*Element)
+	for i, child := range ChildNodes(c.nd) {
+		child.WriteTo(b, opt, el, i)
+	}
+}
+
+// RenderHx renders page for an htmx request. If r is a request of htmx, i.e. it
+// has the HX-Request header, but is not boosted, and the element of the id in
+// the HX-Target header is in page, only the part of page swapped by htmx is
+// rendered: the element itself for SwapOuterHTML, or its content for other
+// swap styles, e.g. SwapInnerHTML, the default of htmx. swap should be the
+// hx-swap of the element triggering the request. Otherwise the whole page is
+// rendered. Errors are reported as Render does.
+func RenderHx(w http.ResponseWriter, r *http.Request, page Node, swap HxSwap, opt RenderOptions) error {
+	w.Header().Add("Vary", "HX-Request, HX-Target")
+	if r.Header.Get("HX-Request") == "true" && r.Header.Get("HX-Boosted") != "true" {
+		if id := r.Header.Get("HX-Target"); id != "" {
+			if target := idSelector(id).First(page); target != nil {
+				if swap == SwapOuterHTML {
+					return Render(w, target, opt)
+				}
+				return Render(w, contentOf{target}, opt)
+			}
+		}
+	}
+	return Render(w, page, opt)
+}
@@ -0,0 +1,91 @@
+package html
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golangplus/testing/assert"
+)
+
+func TestHxTrigger(t *testing.T) {
+	assert.Equal(t, "trigger", Trigger("keyup").Changed().Delay(500*time.Millisecond).String(), "keyup changed delay:500ms")
+	assert.Equal(t, "filter", Trigger("click").Filter("ctrlKey").Once().From("body").String(), "click[ctrlKey] once from:body")
+	assert.Equal(t, "every", Trigger("").Every(2*time.Second).String(), "every 2s")
+
+	v := INPUT("search", "q", "").HxTrigger(Trigger("search"), Trigger("keyup").Throttle(time.Second))
+	assert.StringEqual(t, "input", NodeToHTMLNode(v, DefaultOptions),
+		`<input type="search" name="q" hx-trigger="search, keyup throttle:1s">`)
+}
+
+func TestVoid_HxSwap(t *testing.T) {
+	e := DIV().HxDelete("/items/1").HxTarget("closest tr").HxSwap(SwapOuterHTML, "swap:1s")
+	assert.StringEqual(t, "div", NodeToHTMLNode(e, DefaultOptions),
+		`<div hx-delete="/items/1" hx-target="closest tr" hx-swap="outerHTML swap:1s"></div>`)
+}
+
+func hxPage() *Html {
+	h := HTML("")
+	h.Title("Items")
+	h.Body().Child(
+		H1(T("Items")),
+		UL(LI(T("a")), LI(T("b"))).Attr("id", "items"),
+	)
+	return h
+}
+
+func TestRenderHx(t *testing.T) {
+	full := string(NodeToHTMLNode(hxPage(), DefaultOptions))
+
+	w := httptest.NewRecorder()
+	assert.NoError(t, RenderHx(w, httptest.NewRequest("GET", "/", nil), hxPage(), SwapInnerHTML, DefaultOptions))
+	assert.Equal(t, "full", w.Body.String(), full)
+	assert.Equal(t, "vary", w.Header().Get("Vary"), "HX-Request, HX-Target")
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("HX-Request", "true")
+	r.Header.Set("HX-Target", "items")
+	w = httptest.NewRecorder()
+	assert.NoError(t, RenderHx(w, r, hxPage(), SwapInnerHTML, DefaultOptions))
+	assert.Equal(t, "partial", w.Body.String(), "<li>a<li>b")
+
+	r.Header.Set("HX-Target", "missing")
+	w = httptest.NewRecorder()
+	assert.NoError(t, RenderHx(w, r, hxPage(), SwapInnerHTML, DefaultOptions))
+	assert.Equal(t, "missing target", w.Body.String(), full)
+
+	r.Header.Set("HX-Target", "items")
+	r.Header.Set("HX-Boosted", "true")
+	w = httptest.NewRecorder()
+	assert.NoError(t, RenderHx(w, r, hxPage(), SwapInnerHTML, DefaultOptions))
+	assert.Equal(t, "boosted", w.Body.String(), full)
+}
+
+func TestRenderHx_target(t *testing.T) {
+	page := DIV(
+		P(T("x")).Attr("id", `a&b<"c`),
+		UL(LI(T("a"))).Attr("id", "items"),
+	)
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("HX-Request", "true")
+	r.Header.Set("HX-Target", `a&b<"c`)
+	w := httptest.NewRecorder()
+	assert.NoError(t, RenderHx(w, r, page, SwapInnerHTML, DefaultOptions))
+	assert.Equal(t, "special id", w.Body.String(), "x")
+
+	r.Header.Set("HX-Target", "items")
+	w = httptest.NewRecorder()
+	assert.NoError(t, RenderHx(w, r, page, SwapOuterHTML, DefaultOptions))
+	assert.Equal(t, "outerHTML", w.Body.String(), `<ul id="items"><li>a</ul>`)
+}
+
+func ExampleTrigger() {
+	search := INPUT("search", "q", "").
+		HxGet("/search").
+		HxTrigger(Trigger("input").Changed().Delay(300 * time.Millisecond)).
+		HxTarget("#results")
+	fmt.Println(NodeToHTMLNode(search, DefaultOptions))
+	// OUTPUT:
+	// <input type="search" name="q" hx-get="/search" hx-trigger="input changed delay:300ms" hx-target="#results">
+}
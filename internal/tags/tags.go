@@ -0,0 +1,60 @@
+// Package tags provides the facts about HTML tags shared by the generators and
+// converters of the module.
+package tags
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/gohtml/elements"
+)
+
+// Types are the tag types keyed by tag names.
+var Types = map[string]elements.TagType{}
+
+func init() {
+	for tp, name := range elements.TagNames {
+		if name != "" {
+			Types[name] = elements.TagType(tp)
+		}
+	}
+}
+
+// Void are the void elements in HTML5.
+var Void = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "param": true, "source": true, "track": true, "wbr": true,
+}
+
+// Block are the elements around which whitespace isn't rendered, i.e.
+// block-level and invisible elements.
+var Block = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true, "body": true, "br": true,
+	"caption": true, "col": true, "colgroup": true, "dd": true, "details": true, "dialog": true,
+	"div": true, "dl": true, "dt": true, "fieldset": true, "figcaption": true, "figure": true,
+	"footer": true, "form": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true,
+	"h6": true, "head": true, "header": true, "hgroup": true, "hr": true, "li": true, "link": true,
+	"main": true, "meta": true, "nav": true, "ol": true, "optgroup": true, "option": true, "p": true,
+	"pre": true, "script": true, "section": true, "style": true, "summary": true, "table": true,
+	"tbody": true, "td": true, "template": true, "tfoot": true, "th": true, "thead": true,
+	"title": true, "tr": true, "ul": true,
+}
+
+var spacesRegexp = regexp.MustCompile(`[ \t\n\r\f]+`)
+
+// CollapseSpaces replaces runs of whitespace in s with single spaces. first and
+// last tell whether s is at the start or the end of its parent, where spaces
+// are trimmed.
+func CollapseSpaces(s string, first, last bool) string {
+	if strings.TrimSpace(s) == "" && (first || last) {
+		return ""
+	}
+	s = spacesRegexp.ReplaceAllString(s, " ")
+	if first {
+		s = strings.TrimLeft(s, " ")
+	}
+	if last {
+		s = strings.TrimRight(s, " ")
+	}
+	return s
+}
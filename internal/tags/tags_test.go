@@ -0,0 +1,23 @@
+package tags
+
+import (
+	"testing"
+
+	"github.com/golangplus/testing/assert"
+
+	"github.com/gohtml/elements"
+)
+
+func TestTypes(t *testing.T) {
+	assert.Equal(t, "div", Types["div"], elements.DIVTag)
+	_, ok := Types[""]
+	assert.False(t, "empty name", ok)
+}
+
+func TestCollapseSpaces(t *testing.T) {
+	assert.Equal(t, "middle", CollapseSpaces(" a \n\t b ", false, false), " a b ")
+	assert.Equal(t, "first", CollapseSpaces(" a  b ", true, false), "a b ")
+	assert.Equal(t, "last", CollapseSpaces(" a  b ", false, true), " a b")
+	assert.Equal(t, "spaces in the middle", CollapseSpaces("\n  ", false, false), " ")
+	assert.Equal(t, "spaces at the end", CollapseSpaces("\n  ", false, true), "")
+}
@@ -0,0 +1,43 @@
+package html
+
+import (
+	"encoding/json"
+	"fmt"
+
+	. "github.com/gohtml/elements"
+)
+
+// JSONLD creates a SCRIPT element of type "application/ld+json" with v
+// marshaled as JSON as its content. See package schema for the types of
+// common schema.org data.
+//
+// Characters like '<', '>' and '&' are escaped in JSON strings, so the content
+// can never close the SCRIPT element. If v cannot be marshaled, the error is
+// reported when the element is rendered, so Render returns it.
+func JSONLD(v interface{}) *Element {
+	var content Node
+	if js, err := json.Marshal(v); err != nil {
+		content = failedNode{fmt.Errorf("JSONLD: %v", err)}
+	} else {
+		content = HTMLNode(js)
+	}
+
+	return (&Element{
+		Void: Void{tagType: SCRIPTTag},
+	}).Attr("type", "application/ld+json").Child(content)
+}
+
+// failedNode writes nothing and reports err when it is rendered.
+type failedNode struct {
+	err error
+}
+
+// Implementation of Node interface
+func (f failedNode) Type() TagType {
+	return TextType
+}
+
+// Implementation of Node interface
+func (f failedNode) WriteTo(b Writer, opt RenderOptions, parent *Element, childIndex int) {
+	opt.fail(f.err)
+}
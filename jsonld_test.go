@@ -0,0 +1,38 @@
+package html
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/golangplus/testing/assert"
+
+	"github.com/gohtml/html/schema"
+)
+
+func TestJSONLD_escape(t *testing.T) {
+	assert.StringEqual(t, "script", NodeToHTMLNode(JSONLD(map[string]string{
+		"name": "</script><script>alert(1)</script>",
+	}), DefaultOptions),
+		`<script type="application/ld+json">{"name":"\u003c/script\u003e\u003cscript\u003ealert(1)\u003c/script\u003e"}</script>`)
+
+}
+
+func TestJSONLD_error(t *testing.T) {
+	var b bytes.Buffer
+	err := Render(&b, DIV(JSONLD(make(chan int))), DefaultOptions)
+	assert.Error(t, err)
+	assert.Equal(t, "written", b.Len(), 0)
+}
+
+func ExampleJSONLD() {
+	h := HTML("")
+	h.Head().Child(JSONLD(schema.Organization{
+		Name: "Gopher & Co",
+		URL:  "https://example.com",
+	}))
+	fmt.Println(NodeToHTMLNode(h, DefaultOptions))
+	// OUTPUT:
+	// <!DOCTYPE html>
+	// <meta charset="utf-8"><script type="application/ld+json">{"@context":"https://schema.org","@type":"Organization","name":"Gopher \u0026 Co","url":"https://example.com"}</script>
+}
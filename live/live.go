@@ -0,0 +1,380 @@
+// Package live serves server-driven components: the state of a component is
+// kept on the server, events from the browser are sent back to it, and the
+// changes of its rendering are pushed to the browser with Server-Sent Events.
+//
+// Elements with data-on-<type> attributes, where type is one of click, submit,
+// input and change, send events named by the values of the attributes:
+//
+//	html.BUTTON(html.T("+1")).Attr("data-on-click", "inc")
+//
+// The data-value attribute of the element, or its value if there is none, is
+// sent as the value of the event. Submit events include the values of the
+// form.
+package live
+
+import (
+	"crypto/rand"
+	_ "embed"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gohtml/html"
+	"github.com/gohtml/html/patch"
+)
+
+// Event is an event sent from the browser.
+type Event struct {
+	// The DOM event type, e.g. "click".
+	Type string `json:"type"`
+	// The value of the data-on-<type> attribute.
+	Name  string `json:"name"`
+	Value string `json:"value,omitempty"`
+	// The values of the form of a submit event.
+	Form map[string]string `json:"form,omitempty"`
+}
+
+// Component is a stateful part of a page.
+type Component interface {
+	// Render renders the current state. It is called after every event.
+	Render() *html.Element
+	// HandleEvent changes the state according to an event. Calls of Render and
+	// HandleEvent of a component are never concurrent.
+	HandleEvent(ev Event)
+}
+
+// Runtime is the source of the JS runtime connecting rendered components to
+// the server. It requires the runtime of package patch.
+//
+//go:embed live.js
+var Runtime string
+
+// The default of Handler.Timeout.
+const DefaultTimeout = time.Minute
+
+// The interval of keep-alive comments in event streams.
+var pingInterval = 30 * time.Second
+
+// Handler serves a Component. Each GET of a page creates a new instance of the
+// component, the state of which is kept in a session until the page has been
+// disconnected for Timeout.
+//
+// The page, the event stream and the events are all served at the same URL,
+// so the Handler can be mounted at any path.
+type Handler struct {
+	// Renders the document containing the content, which is the rendered
+	// component. If nil, content is put in the body of an empty document. The
+	// scripts of the runtime are appended to the body.
+	Layout func(r *http.Request, content html.Node) *html.Html
+	// How long a session is kept while no event stream is connected. Expired
+	// sessions are removed every Timeout. Default is DefaultTimeout.
+	Timeout time.Duration
+
+	newComponent func(r *http.Request) Component
+
+	mu       sync.Mutex
+	sessions map[string]*session
+	// Whether the expired sessions are being pruned.
+	pruning bool
+}
+
+// NewHandler returns a Handler creating components by newComponent.
+func NewHandler(newComponent func(r *http.Request) Component) *Handler {
+	return &Handler{
+		newComponent: newComponent,
+		sessions:     make(map[string]*session),
+	}
+}
+
+type session struct {
+	id  string
+	url string
+
+	mu      sync.Mutex
+	comp    Component
+	last    *html.Element
+	pending []patch.Patch
+	// Notified when pending patches are added.
+	notify chan struct{}
+	// The number of connected event streams.
+	streams int
+	// The number of event streams ever connected. Only the last one receives
+	// patches.
+	generation int
+	// When the last event stream was disconnected.
+	idleSince time.Time
+}
+
+// wake notifies the event stream of pending patches.
+func (s *session) wake() {
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// fullUpdate replaces the pending patches with one replacing the whole
+// component, for a browser of which the DOM is unknown. s.mu must be held.
+func (s *session) fullUpdate() {
+	content := html.ChildNodes(s.last)[0]
+	s.pending = []patch.Patch{{
+		Op:   patch.Replace,
+		Path: []int{0},
+		HTML: string(html.NodeToHTMLNode(content, html.RenderOptions{DisableOmit: true})),
+	}}
+	s.wake()
+}
+
+// container wraps the rendered component so that the root of the component
+// can be replaced by patches.
+func (s *session) container(content *html.Element) *html.Element {
+	return html.DIV(content).Attr("data-live", s.id).Attr("data-live-url", s.url)
+}
+
+// update renders the component and queues the patches. s.mu must be held.
+func (s *session) update() {
+	cur := s.container(s.comp.Render())
+	patches := patch.Diff(s.last, cur)
+	s.last = cur
+	if len(patches) == 0 {
+		return
+	}
+	s.pending = append(s.pending, patches...)
+	s.wake()
+}
+
+func newSessionID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b[:]), nil
+}
+
+func (h *Handler) timeout() time.Duration {
+	if h.Timeout > 0 {
+		return h.Timeout
+	}
+	return DefaultTimeout
+}
+
+func (h *Handler) session(id string) *session {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.sessions[id]
+}
+
+// newSession creates a session and starts pruning the expired ones.
+func (h *Handler) newSession(r *http.Request) (*session, error) {
+	id, err := newSessionID()
+	if err != nil {
+		return nil, err
+	}
+	u := *r.URL
+	q := u.Query()
+	q.Set("session", id)
+	u.RawQuery = q.Encode()
+	s := &session{
+		id:        id,
+		url:       u.RequestURI(),
+		comp:      h.newComponent(r),
+		notify:    make(chan struct{}, 1),
+		idleSince: time.Now(),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.sessions[id] = s
+	if !h.pruning {
+		h.pruning = true
+		go h.prune()
+	}
+	return s, nil
+}
+
+// prune removes the expired sessions every Timeout until there are no
+// sessions.
+func (h *Handler) prune() {
+	ticker := time.NewTicker(h.timeout())
+	defer ticker.Stop()
+	for now := range ticker.C {
+		h.mu.Lock()
+		for id, s := range h.sessions {
+			s.mu.Lock()
+			expired := s.streams == 0 && now.Sub(s.idleSince) > h.timeout()
+			s.mu.Unlock()
+			if expired {
+				delete(h.sessions, id)
+			}
+		}
+		done := len(h.sessions) == 0
+		if done {
+			h.pruning = false
+		}
+		h.mu.Unlock()
+		if done {
+			return
+		}
+	}
+}
+
+// acceptsEventStream returns true if the Accept header lists
+// text/event-stream.
+func acceptsEventStream(r *http.Request) bool {
+	for _, accept := range r.Header.Values("Accept") {
+		for _, t := range strings.Split(accept, ",") {
+			if mt, _, err := mime.ParseMediaType(t); err == nil && mt == "text/event-stream" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ServeHTTP serves the page for GETs, the event stream for GETs accepting
+// text/event-stream, and events for POSTs.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodPost:
+		h.serveEvent(w, r)
+	case r.Method != http.MethodGet && r.Method != http.MethodHead:
+		w.Header().Set("Allow", "GET, HEAD, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	case acceptsEventStream(r):
+		h.serveStream(w, r)
+	default:
+		h.servePage(w, r)
+	}
+}
+
+func (h *Handler) servePage(w http.ResponseWriter, r *http.Request) {
+	s, err := h.newSession(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	s.mu.Lock()
+	s.last = s.container(s.comp.Render())
+	content := s.last
+	s.mu.Unlock()
+
+	var page *html.Html
+	if h.Layout != nil {
+		page = h.Layout(r, content)
+	} else {
+		page = html.HTML("")
+		page.Body().Child(content)
+	}
+	page.Body().Child(patch.Script(), html.SCRIPT("", Runtime))
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := html.Render(w, page, html.RenderOptions{CSRF: html.CSRFTokenOf(r)}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h *Handler) serveEvent(w http.ResponseWriter, r *http.Request) {
+	// Requiring JSON prevents forms of other sites from posting events.
+	if mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mt != "application/json" {
+		http.Error(w, "unsupported content type", http.StatusUnsupportedMediaType)
+		return
+	}
+	s := h.session(r.URL.Query().Get("session"))
+	if s == nil {
+		http.Error(w, "session not found", http.StatusNotFound)
+		return
+	}
+
+	var ev Event
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&ev); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	s.comp.HandleEvent(ev)
+	s.update()
+	s.mu.Unlock()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) serveStream(w http.ResponseWriter, r *http.Request) {
+	s := h.session(r.URL.Query().Get("session"))
+	if s == nil {
+		http.Error(w, "session not found", http.StatusNotFound)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	s.mu.Lock()
+	s.streams++
+	if s.generation > 0 {
+		// The browser may have missed patches of the previous stream, e.g. if
+		// a write failed, so a reconnected stream starts with the whole
+		// component.
+		s.fullUpdate()
+	}
+	s.generation++
+	generation := s.generation
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.streams--
+		s.idleSince = time.Now()
+		s.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ping := time.NewTicker(pingInterval)
+	defer ping.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+
+		case <-ping.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+
+		case <-s.notify:
+			s.mu.Lock()
+			if s.generation != generation {
+				// Replaced by a newer stream, which may still need the notification.
+				s.mu.Unlock()
+				s.wake()
+				return
+			}
+			patches := s.pending
+			s.pending = nil
+			s.mu.Unlock()
+			if len(patches) == 0 {
+				continue
+			}
+
+			js, err := json.Marshal(patches)
+			if err != nil {
+				return
+			}
+			if _, err := fmt.Fprintf(w, "event: patch\ndata: %s\n\n", js); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
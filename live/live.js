@@ -0,0 +1,57 @@
+// The runtime of github.com/gohtml/html/live. Requires the runtime of
+// github.com/gohtml/html/patch.
+(function () {
+  "use strict";
+
+  var TYPES = ["click", "submit", "input", "change"];
+
+  function connect(container) {
+    var url = container.getAttribute("data-live-url");
+
+    var es = new EventSource(url);
+    es.addEventListener("patch", function (e) {
+      gohtmlPatch(container, JSON.parse(e.data));
+    });
+    es.onerror = function () {
+      if (es.readyState === EventSource.CLOSED) {
+        // The session has expired.
+        location.reload();
+      }
+    };
+
+    TYPES.forEach(function (type) {
+      container.addEventListener(type, function (e) {
+        var el = e.target.closest && e.target.closest("[data-on-" + type + "]");
+        if (!el || !container.contains(el)) {
+          return;
+        }
+        var ev = {
+          type: type,
+          name: el.getAttribute("data-on-" + type),
+          value: el.hasAttribute("data-value") ? el.getAttribute("data-value") : (el.value || "")
+        };
+        if (type === "submit") {
+          ev.form = {};
+          new FormData(el).forEach(function (v, k) {
+            ev.form[k] = String(v);
+          });
+        }
+        if (type === "click" || type === "submit") {
+          e.preventDefault();
+        }
+
+        fetch(url, {
+          method: "POST",
+          headers: {"Content-Type": "application/json"},
+          body: JSON.stringify(ev)
+        }).then(function (resp) {
+          if (resp.status === 404) {
+            location.reload();
+          }
+        });
+      });
+    });
+  }
+
+  document.querySelectorAll("[data-live]").forEach(connect);
+})();
@@ -0,0 +1,164 @@
+package live
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	stdhtml "html"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golangplus/testing/assert"
+
+	"github.com/gohtml/html"
+	"github.com/gohtml/html/patch"
+)
+
+type counter struct {
+	n int
+}
+
+func (c *counter) Render() *html.Element {
+	return html.DIV(
+		html.SPAN(html.T(strconv.Itoa(c.n))),
+		html.BUTTON(html.T("+")).Attr("data-on-click", "add").Attr("data-value", "1"),
+	)
+}
+
+func (c *counter) HandleEvent(ev Event) {
+	if ev.Name == "add" {
+		d, _ := strconv.Atoi(ev.Value)
+		c.n += d
+	}
+}
+
+var liveURLRegexp = regexp.MustCompile(`data-live-url="([^"]+)"`)
+
+func postEvent(t *testing.T, url string, ev Event) int {
+	js, _ := json.Marshal(ev)
+	resp, err := http.Post(url, "application/json", strings.NewReader(string(js)))
+	assert.NoError(t, err)
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestHandler(t *testing.T) {
+	h := NewHandler(func(r *http.Request) Component { return &counter{} })
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/counter?x=1")
+	assert.NoError(t, err)
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, "content-type", resp.Header.Get("Content-Type"), "text/html; charset=utf-8")
+	assert.True(t, "counter", strings.Contains(string(body), "<span>0</span>"))
+	assert.True(t, "runtime", strings.Contains(string(body), "gohtmlPatch(container"))
+
+	m := liveURLRegexp.FindStringSubmatch(string(body))
+	assert.Equal(t, "len(m)", len(m), 2)
+	url := srv.URL + stdhtml.UnescapeString(m[1])
+	assert.True(t, "url", strings.HasPrefix(url, srv.URL+"/counter?session="))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequest("GET", url, nil)
+	req.Header.Set("Accept", "text/event-stream")
+	stream, err := http.DefaultClient.Do(req.WithContext(ctx))
+	assert.NoError(t, err)
+	defer stream.Body.Close()
+	assert.Equal(t, "content-type", stream.Header.Get("Content-Type"), "text/event-stream")
+
+	assert.Equal(t, "status", postEvent(t, url, Event{Type: "click", Name: "add", Value: "2"}), http.StatusNoContent)
+
+	assert.Equal(t, "patches", readEvent(t, bufio.NewScanner(stream.Body)), []patch.Patch{
+		{Op: patch.SetText, Path: []int{0, 0, 0}, Value: "2"},
+	})
+}
+
+func TestHandler_errors(t *testing.T) {
+	h := NewHandler(func(r *http.Request) Component { return &counter{} })
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	assert.Equal(t, "unknown session", postEvent(t, srv.URL+"/?session=none", Event{Name: "add"}), http.StatusNotFound)
+
+	resp, err := http.Post(srv.URL+"/", "application/x-www-form-urlencoded", strings.NewReader("name=add"))
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "form post", resp.StatusCode, http.StatusUnsupportedMediaType)
+
+	req, _ := http.NewRequest("DELETE", srv.URL+"/", nil)
+	resp, err = http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "delete", resp.StatusCode, http.StatusMethodNotAllowed)
+}
+
+func TestHandler_expire(t *testing.T) {
+	h := NewHandler(func(r *http.Request) Component { return &counter{} })
+	h.Timeout = 10 * time.Millisecond
+	for i := 0; i < 3; i++ {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+		assert.Equal(t, "status", rec.Code, http.StatusOK)
+	}
+	pruned := func() bool {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		return len(h.sessions) == 0 && !h.pruning
+	}
+	for start := time.Now(); !pruned() && time.Since(start) < 5*time.Second; {
+		time.Sleep(time.Millisecond)
+	}
+	assert.True(t, "pruned", pruned())
+}
+
+func readEvent(t *testing.T, sc *bufio.Scanner) []patch.Patch {
+	var lines []string
+	for len(lines) < 2 && sc.Scan() {
+		lines = append(lines, sc.Text())
+	}
+	assert.Equal(t, "event", lines[0], "event: patch")
+	var patches []patch.Patch
+	assert.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(lines[1], "data: ")), &patches))
+	return patches
+}
+
+func TestHandler_reconnect(t *testing.T) {
+	h := NewHandler(func(r *http.Request) Component { return &counter{} })
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/")
+	assert.NoError(t, err)
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	url := srv.URL + stdhtml.UnescapeString(liveURLRegexp.FindStringSubmatch(string(body))[1])
+
+	connect := func() *http.Response {
+		req, _ := http.NewRequest("GET", url, nil)
+		req.Header.Set("Accept", "text/html, text/event-stream;q=0.9")
+		stream, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		assert.Equal(t, "content-type", stream.Header.Get("Content-Type"), "text/event-stream")
+		return stream
+	}
+	connect().Body.Close()
+	// Patches may be lost while no stream is connected.
+	assert.Equal(t, "status", postEvent(t, url, Event{Type: "click", Name: "add", Value: "3"}), http.StatusNoContent)
+
+	stream := connect()
+	defer stream.Body.Close()
+	assert.Equal(t, "patches", readEvent(t, bufio.NewScanner(stream.Body)), []patch.Patch{{
+		Op:   patch.Replace,
+		Path: []int{0},
+		HTML: `<div><span>3</span><button data-on-click="add" data-value="1">+</button></div>`,
+	}})
+}
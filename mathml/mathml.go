@@ -0,0 +1,89 @@
+// Package mathml contains constructors of MathML elements embedded in HTML.
+//
+// The elements are html.Foreign, so they can be children of any HTML element,
+// e.g. html.P, and are rendered as foreign content. The namespace is implied
+// by the math element in HTML, so it is not exported unless set explicitly,
+// e.g. MATH("").Attr("xmlns", mathml.Namespace) for XHTML.
+package mathml
+
+import (
+	"github.com/gohtml/html"
+)
+
+// The namespace of MathML.
+const Namespace = "http://www.w3.org/1998/Math/MathML"
+
+// MATH creates a math element. display is "block" or "inline", ignored if empty.
+func MATH(display string, children ...html.Node) *html.Foreign {
+	return html.FOREIGN("math", children...).NonEmptyAttr("display", display)
+}
+
+// MI creates an mi element, an identifier.
+func MI(name string) *html.Foreign {
+	return html.FOREIGN("mi").T(name)
+}
+
+// MN creates an mn element, a number.
+func MN(num string) *html.Foreign {
+	return html.FOREIGN("mn").T(num)
+}
+
+// MO creates an mo element, an operator.
+func MO(op string) *html.Foreign {
+	return html.FOREIGN("mo").T(op)
+}
+
+// MTEXT creates an mtext element.
+func MTEXT(text string) *html.Foreign {
+	return html.FOREIGN("mtext").T(text)
+}
+
+// MROW creates an mrow element grouping the children.
+func MROW(children ...html.Node) *html.Foreign {
+	return html.FOREIGN("mrow", children...)
+}
+
+// MFRAC creates an mfrac element.
+func MFRAC(numerator, denominator html.Node) *html.Foreign {
+	return html.FOREIGN("mfrac", numerator, denominator)
+}
+
+// MSQRT creates an msqrt element.
+func MSQRT(children ...html.Node) *html.Foreign {
+	return html.FOREIGN("msqrt", children...)
+}
+
+// MROOT creates an mroot element.
+func MROOT(base, index html.Node) *html.Foreign {
+	return html.FOREIGN("mroot", base, index)
+}
+
+// MSUP creates an msup element.
+func MSUP(base, superscript html.Node) *html.Foreign {
+	return html.FOREIGN("msup", base, superscript)
+}
+
+// MSUB creates an msub element.
+func MSUB(base, subscript html.Node) *html.Foreign {
+	return html.FOREIGN("msub", base, subscript)
+}
+
+// MSUBSUP creates an msubsup element.
+func MSUBSUP(base, subscript, superscript html.Node) *html.Foreign {
+	return html.FOREIGN("msubsup", base, subscript, superscript)
+}
+
+// MTABLE creates an mtable element. rows are usually MTRs.
+func MTABLE(rows ...html.Node) *html.Foreign {
+	return html.FOREIGN("mtable", rows...)
+}
+
+// MTR creates an mtr element. cells are usually MTDs.
+func MTR(cells ...html.Node) *html.Foreign {
+	return html.FOREIGN("mtr", cells...)
+}
+
+// MTD creates an mtd element.
+func MTD(children ...html.Node) *html.Foreign {
+	return html.FOREIGN("mtd", children...)
+}
@@ -0,0 +1,31 @@
+package mathml
+
+import (
+	"fmt"
+
+	"github.com/gohtml/html"
+)
+
+func ExampleMATH() {
+	// x = (-b ± √(b²-4ac)) / 2a
+	formula := MATH("block",
+		MI("x"), MO("="),
+		MFRAC(
+			MROW(MO("-"), MI("b"), MO("±"), MSQRT(MSUP(MI("b"), MN("2")), MO("-"), MN("4"), MI("a"), MI("c"))),
+			MROW(MN("2"), MI("a")),
+		),
+	)
+	fmt.Println(html.NodeToHTMLNode(html.DIV(html.P(html.T("Roots: "), formula)), html.RenderOptions{SortAttr: true}))
+	// OUTPUT:
+	// <div><p>Roots: <math display="block"><mi>x</mi><mo>=</mo><mfrac><mrow><mo>-</mo><mi>b</mi><mo>±</mo><msqrt><msup><mi>b</mi><mn>2</mn></msup><mo>-</mo><mn>4</mn><mi>a</mi><mi>c</mi></msqrt></mrow><mrow><mn>2</mn><mi>a</mi></mrow></mfrac></math></div>
+}
+
+func ExampleMTABLE() {
+	m := MATH("", MTABLE(
+		MTR(MTD(MN("1")), MTD(MN("0"))),
+		MTR(MTD(MN("0")), MTD(MN("1"))),
+	)).Attr("xmlns", Namespace)
+	fmt.Println(html.NodeToHTMLNode(m, html.RenderOptions{SortAttr: true}))
+	// OUTPUT:
+	// <math xmlns="http://www.w3.org/1998/Math/MathML"><mtable><mtr><mtd><mn>1</mn></mtd><mtd><mn>0</mn></mtd></mtr><mtr><mtd><mn>0</mn></mtd><mtd><mn>1</mn></mtd></mtr></mtable></math>
+}
@@ -0,0 +1,151 @@
+package html
+
+import (
+	"strconv"
+)
+
+// Pager describes the pages of a listing.
+type Pager struct {
+	// The total number of items.
+	Total   int
+	PerPage int
+	// The current page, starting from 1.
+	Current int
+	// The number of pages shown on each side of the current page. Default is 2.
+	Window int
+}
+
+// Pages returns the number of pages.
+func (p Pager) Pages() int {
+	if p.PerPage <= 0 || p.Total <= 0 {
+		return 1
+	}
+	return (p.Total + p.PerPage - 1) / p.PerPage
+}
+
+// current returns the current page limited in the range of pages.
+func (p Pager) current() int {
+	if p.Current < 1 {
+		return 1
+	}
+	if n := p.Pages(); p.Current > n {
+		return n
+	}
+	return p.Current
+}
+
+// Links returns the page numbers to show, in which zeros are ellipses. The
+// first and the last pages are always shown. An ellipsis never replaces a
+// single page.
+func (p Pager) Links() []int {
+	n, cur, w := p.Pages(), p.current(), p.Window
+	if w <= 0 {
+		w = 2
+	}
+
+	from, to := cur-w, cur+w
+	if from <= 3 {
+		from = 1
+	}
+	if to >= n-2 {
+		to = n
+	}
+
+	var links []int
+	if from > 1 {
+		links = append(links, 1, 0)
+	}
+	for i := from; i <= to; i++ {
+		links = append(links, i)
+	}
+	if to < n {
+		links = append(links, 0, n)
+	}
+	return links
+}
+
+// Pagination creates a NAV element of links to the pages. url returns the URL
+// of a page. The link of the current page is marked by aria-current="page".
+// Links to the previous and the next pages are included if they exist.
+func Pagination(p Pager, url func(page int) string) *Element {
+	n, cur := p.Pages(), p.current()
+	ul := UL()
+	if cur > 1 {
+		ul.Child(LI(A(url(cur-1), T("Previous")).Attr("rel", "prev")))
+	}
+	for _, page := range p.Links() {
+		if page == 0 {
+			ul.Child(LI(SPAN(T("…")).Attr("aria-hidden", "true")))
+			continue
+		}
+
+		a := A(url(page), T(strconv.Itoa(page)))
+		if page == cur {
+			a.Attr("aria-current", "page")
+		} else {
+			a.Attr("aria-label", "Page "+strconv.Itoa(page))
+		}
+		ul.Child(LI(a))
+	}
+	if cur < n {
+		ul.Child(LI(A(url(cur+1), T("Next")).Attr("rel", "next")))
+	}
+
+	return NAV(ul).Attr("aria-label", "Pagination")
+}
+
+// Crumb is an item of Breadcrumbs.
+type Crumb struct {
+	Title string
+	URL   string
+}
+
+// Breadcrumbs creates a NAV element with an OL of links to the crumbs. The last
+// crumb is the current page and is marked by aria-current="page".
+func Breadcrumbs(crumbs ...Crumb) *Element {
+	ol := OL()
+	for i, c := range crumbs {
+		var item *Element
+		if c.URL != "" {
+			item = A(c.URL, T(c.Title))
+		} else {
+			item = SPAN(T(c.Title))
+		}
+		if i == len(crumbs)-1 {
+			item.Attr("aria-current", "page")
+		}
+		ol.Child(LI(item))
+	}
+
+	return NAV(ol).Attr("aria-label", "Breadcrumb")
+}
+
+// NavItem is an item of NavMenu.
+type NavItem struct {
+	Title string
+	URL   string
+	// Items of the submenu.
+	Items []NavItem
+}
+
+func navList(items []NavItem, current string) *Element {
+	ul := UL()
+	for _, item := range items {
+		a := A(item.URL, T(item.Title))
+		if item.URL == current {
+			a.Attr("aria-current", "page")
+		}
+		li := LI(a)
+		if len(item.Items) > 0 {
+			li.Child(navList(item.Items, current))
+		}
+		ul.Child(li)
+	}
+	return ul
+}
+
+// NavMenu creates a NAV element labeled by label, with nested lists of links to
+// the items. The link to current is marked by aria-current="page".
+func NavMenu(label, current string, items ...NavItem) *Element {
+	return NAV(navList(items, current)).Attr("aria-label", label)
+}
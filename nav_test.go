@@ -0,0 +1,55 @@
+package html
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golangplus/testing/assert"
+)
+
+func TestPager_Links(t *testing.T) {
+	for _, c := range []struct {
+		p     Pager
+		links []int
+	}{
+		{Pager{Total: 0, PerPage: 10, Current: 1}, []int{1}},
+		{Pager{Total: 50, PerPage: 10, Current: 3}, []int{1, 2, 3, 4, 5}},
+		{Pager{Total: 200, PerPage: 10, Current: 1}, []int{1, 2, 3, 0, 20}},
+		{Pager{Total: 200, PerPage: 10, Current: 4}, []int{1, 2, 3, 4, 5, 6, 0, 20}},
+		{Pager{Total: 200, PerPage: 10, Current: 10}, []int{1, 0, 8, 9, 10, 11, 12, 0, 20}},
+		{Pager{Total: 200, PerPage: 10, Current: 20, Window: 1}, []int{1, 0, 19, 20}},
+		{Pager{Total: 200, PerPage: 10, Current: 30}, []int{1, 0, 18, 19, 20}},
+	} {
+		assert.Equal(t, fmt.Sprintf("%+v", c.p), c.p.Links(), c.links)
+	}
+}
+
+func TestBreadcrumbs(t *testing.T) {
+	assert.StringEqual(t, "nav", NodeToHTMLNode(Breadcrumbs(
+		Crumb{"Home", "/"},
+		Crumb{"Books", "/books"},
+		Crumb{"Go", ""},
+	), RenderOptions{SortAttr: true}),
+		`<nav aria-label="Breadcrumb"><ol><li><a href="/">Home</a><li><a href="/books">Books</a>`+
+			`<li><span aria-current="page">Go</span></ol></nav>`)
+}
+
+func TestNavMenu(t *testing.T) {
+	assert.StringEqual(t, "nav", NodeToHTMLNode(NavMenu("Main", "/docs/start",
+		NavItem{Title: "Home", URL: "/"},
+		NavItem{Title: "Docs", URL: "/docs", Items: []NavItem{
+			{Title: "Start", URL: "/docs/start"},
+		}},
+	), RenderOptions{SortAttr: true}),
+		`<nav aria-label="Main"><ul><li><a href="/">Home</a><li><a href="/docs">Docs</a>`+
+			`<ul><li><a aria-current="page" href="/docs/start">Start</a></ul></ul></nav>`)
+}
+
+func ExamplePagination() {
+	nav := Pagination(Pager{Total: 95, PerPage: 10, Current: 2}, func(page int) string {
+		return fmt.Sprintf("/items?page=%d", page)
+	})
+	fmt.Println(NodeToHTMLNode(nav, RenderOptions{SortAttr: true}))
+	// OUTPUT:
+	// <nav aria-label="Pagination"><ul><li><a href="/items?page=1" rel="prev">Previous</a><li><a aria-label="Page 1" href="/items?page=1">1</a><li><a aria-current="page" href="/items?page=2">2</a><li><a aria-label="Page 3" href="/items?page=3">3</a><li><a aria-label="Page 4" href="/items?page=4">4</a><li><span aria-hidden="true">…</span><li><a aria-label="Page 10" href="/items?page=10">10</a><li><a href="/items?page=3" rel="next">Next</a></ul></nav>
+}
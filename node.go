@@ -12,13 +12,42 @@ import (
 	"github.com/gohtml/utils"
 )
 
+// RenderOptions controls how nodes are rendered.
+//
+// Rendering never modifies the nodes, so a tree can be rendered from multiple
+// goroutines concurrently as long as no goroutine is modifying it at the same time.
 type RenderOptions struct {
 	Ident HTMLNode
 	// Don't apply HTML5 omitting.
 	DisableOmit bool
 	// Sort attributes names before export.
 	// This is useful for testing because otherwise the exported attributes could be unpredictable.
+	// It is same as setting AttrOrder to AlphabeticalOrder.
 	SortAttr bool
+	// The order of exported attributes. Default is InsertionOrder.
+	AttrOrder AttrOrder
+	// If not nil, a hidden INPUT of the token is injected into FORMs.
+	CSRF *CSRFToken
+	// The manifest to resolve references created by Asset.
+	Assets *AssetManifest
+
+	// Errors reported during rendering, set by Render.
+	errs *[]error
+}
+
+// fail reports an error during rendering. Errors are returned by Render, and
+// ignored by NodeToHTMLNode.
+func (opt *RenderOptions) fail(err error) {
+	if opt.errs != nil {
+		*opt.errs = append(*opt.errs, err)
+	}
+}
+
+func (opt *RenderOptions) attrOrder() AttrOrder {
+	if opt.AttrOrder == InsertionOrder && opt.SortAttr {
+		return AlphabeticalOrder
+	}
+	return opt.AttrOrder
 }
 
 // The default RenderOptions
@@ -35,6 +64,39 @@ type Node interface {
 	WriteTo(w Writer, opt RenderOptions, parent *Element, childIndex int)
 }
 
+// Walk traverses the tree rooted at nd in depth-first order and calls fn for
+// each node. Children of a node are traversed if it has a Children() []Node
+// method and fn returns true for it.
+func Walk(nd Node, fn func(nd Node) bool) {
+	if !fn(nd) {
+		return
+	}
+
+	if p, ok := nd.(interface {
+		Children() []Node
+	}); ok {
+		for _, child := range p.Children() {
+			Walk(child, fn)
+		}
+	}
+}
+
+// Render renders a Node into w. Nothing is written if any error is reported
+// during rendering, e.g. an unknown asset, and the first error is returned.
+func Render(w io.Writer, nd Node, opt RenderOptions) error {
+	var errs []error
+	opt.errs = &errs
+
+	var b bytesp.ByteSlice
+	nd.WriteTo(&b, opt, nil, 0)
+	if len(errs) > 0 {
+		return errs[0]
+	}
+
+	_, err := w.Write(b)
+	return err
+}
+
 // NodeToHTMLBytes converts a Node into HTMLNode.
 func NodeToHTMLNode(nd Node, opt RenderOptions) HTMLNode {
 	var b bytesp.ByteSlice
@@ -59,15 +121,76 @@ func (h HTMLNode) Type() TagType {
 	return TextType
 }
 
+// RawType is the type of Raw nodes.
+const RawType TagType = -2
+
+// Raw is trusted HTML markup written as it is, e.g. the output of a template,
+// which may contain elements and comments. Unlike HTMLNode, which is a text,
+// its structure is unknown, so the tags next to it are never omitted.
+type Raw string
+
+var _ Node = Raw("")
+
+func (r Raw) WriteTo(b Writer, opt RenderOptions, parent *Element, childIndex int) {
+	b.WriteString(string(r))
+}
+
+func (r Raw) Type() TagType {
+	return RawType
+}
+
+// Nodes is a list of sibling nodes rendered one after another, e.g. the result
+// of a template with multiple roots.
+type Nodes []Node
+
+var _ Node = Nodes(nil)
+
+// Type returns the type of the first node so that omission of the end tag of
+// the previous sibling is correct. It returns TextType if ns is empty.
+func (ns Nodes) Type() TagType {
+	if len(ns) == 0 {
+		return TextType
+	}
+	return ns[0].Type()
+}
+
+// Implementation of Node interface. The nodes are rendered without the parent
+// since their positions in it are unknown, so their end tags are kept.
+func (ns Nodes) WriteTo(b Writer, opt RenderOptions, parent *Element, childIndex int) {
+	for i, nd := range ns {
+		nd.WriteTo(b, opt, nil, i)
+	}
+}
+
+// Children returns the nodes.
+func (ns Nodes) Children() []Node {
+	return ns
+}
+
 // An HTML void element
 type Void struct {
 	tagType    TagType
 	attributes Attributes
 	classes    htmlNodeSet
+	// Declarations set by Style.
+	style Style
 }
 
 var _ Node = (*Void)(nil)
 
+// voidOf returns the Void part of a *Void or *Element, or nil for other nodes.
+func voidOf(nd Node) *Void {
+	switch v := nd.(type) {
+	case *Void:
+		return v
+	case *Element:
+		return &v.Void
+	case *Html:
+		return &v.Void
+	}
+	return nil
+}
+
 func (v *Void) Type() TagType {
 	return v.tagType
 }
@@ -77,18 +200,30 @@ func (v *Void) WriteTo(b Writer, opt RenderOptions, parent *Element, childIndex
 	b.WriteByte('<')
 	b.WriteString(TagNames[v.tagType])
 
-	if len(v.classes) > 0 {
+	writeAttrs(b, opt, v.attributes, v.classes)
+
+	b.WriteByte('>')
+}
+
+// writeAttrs exports the classes and the attributes in the order of opt.
+func writeAttrs(b Writer, opt RenderOptions, attrs Attributes, classes htmlNodeSet) {
+	order := opt.attrOrder()
+	if order == CanonicalOrder {
+		if i := attrs.index("id"); i >= 0 {
+			attrs[i].writeTo(b, &opt)
+		}
+	}
+
+	if len(classes) > 0 {
 		b.WriteString(` class="`)
-		v.classes[0].WriteRaw(b)
-		for i, n := 1, len(v.classes); i < n; i++ {
+		classes[0].WriteRaw(b)
+		for i, n := 1, len(classes); i < n; i++ {
 			b.WriteByte(' ')
-			v.classes[i].WriteRaw(b)
+			classes[i].WriteRaw(b)
 		}
 		b.WriteByte('"')
 	}
-	v.attributes.WriteTo(b, opt.SortAttr)
-
-	b.WriteByte('>')
+	attrs.writeOrdered(b, order, &opt)
 }
 
 func (v *Void) Name() HTMLNode {
@@ -125,6 +260,11 @@ func (v *Void) attrOfEscaped(name, value HTMLNode) *Void {
 		return v
 	}
 
+	if name == "style" {
+		// The style attribute is replaced, forget declarations set by Style.
+		v.style = Style{}
+	}
+
 	v.attributes.Put(name, value)
 	return v
 }
@@ -139,6 +279,18 @@ func (v *Void) TabIndex(tablInex int) {
 	v.Attr("tabindex", strconv.Itoa(tablInex))
 }
 
+// GetAttr returns the unescaped value of an attribute. The value of "class"
+// is the list of classes separated by spaces.
+func (v *Void) GetAttr(name string) (string, bool) {
+	return getAttr(v.attributes, v.classes, name)
+}
+
+// EachAttr calls fn with the name and the unescaped value of every attribute,
+// starting with the class.
+func (v *Void) EachAttr(fn func(name, value string)) {
+	eachAttr(v.attributes, v.classes, fn)
+}
+
 // NonEmptyAttr sets the attribute is value is not empty.
 func (v *Void) NonEmptyAttr(name, value string) *Void {
 	if value == "" {
@@ -179,8 +331,8 @@ type Element struct {
 var _ Node = (*Element)(nil)
 
 // Children returns the children of the element.
-func (t *Element) Children() []Element {
-	return t.Children()
+func (t *Element) Children() []Node {
+	return t.children
 }
 
 // Attr is same as Void.Attr but returns a *Element.
@@ -249,10 +401,22 @@ func shouldNewLine(e *Element) bool {
 }
 
 func (e *Element) WriteTo(b Writer, opt RenderOptions, parent *Element, childIndex int) {
-	// TODO omit and indent
-	if opt.DisableOmit || !canElementOmitStartTag(e, parent, childIndex) {
+	// TODO indent
+	e.writeTo(b, opt,
+		!opt.DisableOmit && canElementOmitStartTag(e, parent, childIndex),
+		!opt.DisableOmit && canElementOmitEndTag(e, parent, childIndex))
+}
+
+// writeTo exports the element. Omission of its own tags is decided by the
+// caller, and that of descendants by opt.
+func (e *Element) writeTo(b Writer, opt RenderOptions, omitStart, omitEnd bool) {
+	if !omitStart {
 		// Write the open tag including attributes
-		e.Void.WriteTo(b, opt, parent, childIndex)
+		e.Void.WriteTo(b, opt, nil, 0)
+	}
+
+	if e.tagType == FORMTag && opt.CSRF != nil && opt.CSRF.injects(e) {
+		opt.CSRF.writeTo(b, opt, e)
 	}
 
 	if shouldNewLine(e) {
@@ -263,7 +427,7 @@ func (e *Element) WriteTo(b Writer, opt RenderOptions, parent *Element, childInd
 		child.WriteTo(b, opt, e, i)
 	}
 
-	if !opt.DisableOmit && canElementOmitEndTag(e, parent, childIndex) {
+	if omitEnd {
 		return
 	}
 
@@ -1,6 +1,7 @@
 package html
 
 import (
+	"sync"
 	"testing"
 
 	"github.com/golangplus/testing/assert"
@@ -25,3 +26,62 @@ func TestVoid(t *testing.T) {
 	assert.StringEqual(t, "div", NodeToHTMLNode(div, DefaultOptions),
 		`<div tabindex="1024">`)
 }
+
+func TestVoid_attrOrder(t *testing.T) {
+	div := DIV().Attr("title", "T").Attr("id", "main").AddClass("a", "b")
+
+	assert.StringEqual(t, "div", NodeToHTMLNode(div, DefaultOptions),
+		`<div class="a b" title="T" id="main"></div>`)
+	assert.StringEqual(t, "div", NodeToHTMLNode(div, RenderOptions{AttrOrder: AlphabeticalOrder}),
+		`<div class="a b" id="main" title="T"></div>`)
+	assert.StringEqual(t, "div", NodeToHTMLNode(div, RenderOptions{AttrOrder: CanonicalOrder}),
+		`<div id="main" class="a b" title="T"></div>`)
+	// Rendering must not change the insertion order.
+	assert.StringEqual(t, "div", NodeToHTMLNode(div, DefaultOptions),
+		`<div class="a b" title="T" id="main"></div>`)
+}
+
+func TestNodeToHTMLNode_concurrent(t *testing.T) {
+	ul := UL()
+	for i := 0; i < 10; i++ {
+		ul.Child(LI(T("item")).Attr("z", "1").Attr("y", "2").Attr("x", "3"))
+	}
+	exp := NodeToHTMLNode(ul, RenderOptions{SortAttr: true})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.StringEqual(t, "ul", NodeToHTMLNode(ul, RenderOptions{SortAttr: true}), exp)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestVoid_GetAttr(t *testing.T) {
+	a := A("/?a=1&b=2", T("x")).AddClass("big", "red")
+
+	href, ok := a.GetAttr("href")
+	assert.True(t, "ok", ok)
+	assert.Equal(t, "href", href, "/?a=1&b=2")
+	cls, _ := a.GetAttr("class")
+	assert.Equal(t, "class", cls, "big red")
+	_, ok = a.GetAttr("title")
+	assert.False(t, "ok", ok)
+
+	var names []string
+	a.EachAttr(func(name, value string) {
+		names = append(names, name)
+	})
+	assert.Equal(t, "names", names, []string{"class", "href"})
+}
+
+func TestNodes(t *testing.T) {
+	ns := Nodes{LI(T("a")), LI(T("b"))}
+	assert.Equal(t, "type", ns.Type(), LITag)
+	assert.Equal(t, "empty type", Nodes(nil).Type(), TextType)
+	assert.StringEqual(t, "html", NodeToHTMLNode(ns, DefaultOptions), `<li>a</li><li>b</li>`)
+	assert.StringEqual(t, "in UL", NodeToHTMLNode(UL(ns), DefaultOptions), `<ul><li>a</li><li>b</li></ul>`)
+	assert.Equal(t, "select", len(Select(DIV(ns), "li")), 2)
+}
@@ -7,10 +7,31 @@ import (
 
 // Based on http://www.w3.org/TR/html5/syntax.html#syntax-tag-omission
 
+// startsWithSpace returns true if a text node starts with a space. Text nodes
+// other than HTMLNode, e.g. a static text, are assumed to.
+func startsWithSpace(nd Node) bool {
+	t, ok := nd.(HTMLNode)
+	return !ok || utils.StartWithSpace(string(t))
+}
+
+// nextToRaw returns true if the start or end tag of e is next to a Raw node,
+// which is opaque.
+func nextToRaw(e, parent *Element, childIndex int, start bool) bool {
+	if start {
+		return len(e.children) > 0 && e.children[0].Type() == RawType ||
+			parent != nil && childIndex > 0 && parent.children[childIndex-1].Type() == RawType
+	}
+	return len(e.children) > 0 && e.children[len(e.children)-1].Type() == RawType ||
+		parent != nil && childIndex+1 < len(parent.children) && parent.children[childIndex+1].Type() == RawType
+}
+
 func canElementOmitStartTag(e, parent *Element, childIndex int) bool {
 	if len(e.attributes) > 0 || len(e.classes) > 0 {
 		return false
 	}
+	if nextToRaw(e, parent, childIndex, true) {
+		return false
+	}
 
 	switch e.tagType {
 	case HTMLTag, HEADTag:
@@ -22,7 +43,7 @@ func canElementOmitStartTag(e, parent *Element, childIndex int) bool {
 		}
 		switch e.children[0].Type() {
 		case TextType:
-			return !utils.StartWithSpace(string(e.children[0].(HTMLNode)))
+			return !startsWithSpace(e.children[0])
 
 		case METATag, LINKTag, SCRIPTTag, TEMPLATETag:
 			return false
@@ -96,14 +117,23 @@ var pOmittedAfter = []bool{
 }
 
 func canElementOmitEndTag(e, parent *Element, childIndex int) bool {
+	if nextToRaw(e, parent, childIndex, false) {
+		return false
+	}
+
 	switch tp := e.Type(); tp {
 	case HTMLTag, HEADTag, BODYTag:
 		return true
+	}
+
+	if parent == nil {
+		// Rendered out of context, e.g. as a fragment. Whether the end tag can be
+		// omitted depends on the following siblings, which are unknown.
+		return false
+	}
 
+	switch tp := e.Type(); tp {
 	case LITag:
-		if parent == nil {
-			return false
-		}
 		if childIndex == len(parent.children)-1 {
 			return true
 		}
@@ -160,8 +190,8 @@ func canElementOmitEndTag(e, parent *Element, childIndex int) bool {
 			return true
 		}
 
-		if parent.children[childIndex+1].Type() == TextType {
-			return !utils.StartWithSpace(string(e.children[childIndex+1].(HTMLNode)))
+		if next := parent.children[childIndex+1]; next.Type() == TextType {
+			return !startsWithSpace(next)
 		}
 
 		return true
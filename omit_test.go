@@ -102,3 +102,15 @@ func TestOmittedTags_table(t *testing.T) {
 		`<!DOCTYPE html>
 <meta charset="utf-8"><table><col span="2"><col><thead><tr><th><tbody><tr><td><tfoot></table>`)
 }
+
+func TestOmittedTags_raw(t *testing.T) {
+	h := HTML("")
+	h.Body().Child(Raw("<!-- c -->"), UL(
+		LI(T("a")),
+		Raw("<li>b"),
+		LI(T("c")),
+	))
+	assert.StringEqual(t, "html", NodeToHTMLNode(h, RenderOptions{SortAttr: true}),
+		`<!DOCTYPE html>
+<meta charset="utf-8"><body><!-- c --><ul><li>a</li><li>b<li>c</ul>`)
+}
@@ -0,0 +1,278 @@
+// Package patch computes the differences between two html.Node trees as
+// patches which can be applied to the DOM rendered from the old tree.
+//
+// The patches are encoded as JSON by encoding/json, and applied by the JS
+// runtime Runtime in browsers:
+//
+//	gohtmlPatch(root, patches)
+//
+// where root is the DOM element rendered from the root of the old tree.
+//
+// Paths are indexes of children from the root, counting elements and
+// non-whitespace texts only. Adjacent texts are treated as one. Since the
+// browser may change the structure while parsing, e.g. by inserting a TBODY
+// into a TABLE, trees should be written the way they are parsed.
+package patch
+
+import (
+	_ "embed"
+	stdhtml "html"
+	"sort"
+	"strings"
+
+	. "github.com/gohtml/elements"
+	"github.com/gohtml/html"
+)
+
+// Op is the operation of a Patch.
+type Op string
+
+const (
+	// Inserts HTML as the child of the node at Index.
+	Insert Op = "insert"
+	// Removes the node.
+	Remove Op = "remove"
+	// Replaces the node with HTML.
+	Replace Op = "replace"
+	// Moves the child of the node at From to Index. Index is the position after
+	// the child is removed.
+	Move Op = "move"
+	// Sets the attribute Name of the node to Value.
+	SetAttr Op = "attr"
+	// Removes the attribute Name of the node.
+	RemoveAttr Op = "rmattr"
+	// Sets the text of the text node to Value.
+	SetText Op = "text"
+)
+
+// KeyAttr is the attribute of explicit keys of elements. Children with the same
+// key, or with the same id if there is no key, are matched between the old and
+// new trees, so reordered children are moved instead of replaced. Other
+// children are matched in order.
+const KeyAttr = "data-key"
+
+// Patch is an operation on the DOM. Patches should be applied in order since
+// paths refer to the DOM with the previous patches applied.
+type Patch struct {
+	Op Op `json:"op"`
+	// The indexes of the children from the root to the node.
+	Path  []int  `json:"path"`
+	Index int    `json:"index,omitempty"`
+	From  int    `json:"from,omitempty"`
+	Name  string `json:"name,omitempty"`
+	Value string `json:"value,omitempty"`
+	HTML  string `json:"html,omitempty"`
+}
+
+// Runtime is the source of the JS runtime that defines gohtmlPatch(root,
+// patches).
+//
+//go:embed patch.js
+var Runtime string
+
+// Script returns a SCRIPT element containing Runtime.
+func Script() *html.Element {
+	return html.SCRIPT("", Runtime)
+}
+
+// renderOptions is used to render inserted nodes. End tags are never omitted
+// since the HTML is parsed out of its context.
+var renderOptions = html.RenderOptions{DisableOmit: true}
+
+func render(nd html.Node) string {
+	return string(html.NodeToHTMLNode(nd, renderOptions))
+}
+
+func name(nd html.Node) (string, bool) {
+	if n, ok := nd.(interface{ Name() html.HTMLNode }); ok {
+		return string(n.Name()), true
+	}
+	return "", false
+}
+
+func attrs(nd html.Node) map[string]string {
+	res := make(map[string]string)
+	if an, ok := nd.(interface {
+		EachAttr(fn func(name, value string))
+	}); ok {
+		an.EachAttr(func(name, value string) {
+			res[name] = value
+		})
+	}
+	return res
+}
+
+func keyOf(nd html.Node) string {
+	an, ok := nd.(interface {
+		GetAttr(name string) (string, bool)
+	})
+	if !ok {
+		return ""
+	}
+	if key, ok := an.GetAttr(KeyAttr); ok {
+		return "k:" + key
+	}
+	if id, ok := an.GetAttr("id"); ok && id != "" {
+		return "i:" + id
+	}
+	return ""
+}
+
+// children returns the children of nd as they are in the DOM: adjacent texts
+// are merged and whitespace-only texts are ignored.
+func children(nd html.Node) []html.Node {
+	var (
+		res []html.Node
+		txt strings.Builder
+	)
+	flush := func() {
+		if strings.TrimSpace(txt.String()) != "" {
+			res = append(res, html.HTMLNode(txt.String()))
+		}
+		txt.Reset()
+	}
+	for _, child := range html.ChildNodes(nd) {
+		if child.Type() == TextType {
+			txt.WriteString(render(child))
+			continue
+		}
+		flush()
+		res = append(res, child)
+	}
+	flush()
+	return res
+}
+
+type differ struct {
+	patches []Patch
+}
+
+func (d *differ) add(p Patch, path []int) {
+	p.Path = append([]int{}, path...)
+	d.patches = append(d.patches, p)
+}
+
+// Diff returns the patches that transform the DOM rendered from old into the
+// DOM of new.
+func Diff(old, new html.Node) []Patch {
+	var d differ
+	d.node(old, new, nil)
+	return d.patches
+}
+
+func (d *differ) node(old, new html.Node, path []int) {
+	if old.Type() == TextType || new.Type() == TextType {
+		oldText, newText := render(old), render(new)
+		switch {
+		case old.Type() != new.Type():
+			d.add(Patch{Op: Replace, HTML: newText}, path)
+		case oldText == newText:
+		case strings.ContainsRune(newText, '<'):
+			// Raw HTML.
+			d.add(Patch{Op: Replace, HTML: newText}, path)
+		default:
+			d.add(Patch{Op: SetText, Value: stdhtml.UnescapeString(newText)}, path)
+		}
+		return
+	}
+
+	oldName, oldOk := name(old)
+	newName, newOk := name(new)
+	_, oldEl := old.(interface{ Children() []html.Node })
+	_, newEl := new.(interface{ Children() []html.Node })
+	if !oldOk || !newOk || oldName != newName || oldEl != newEl || keyOf(old) != keyOf(new) {
+		if oldHTML, newHTML := render(old), render(new); oldHTML != newHTML {
+			d.add(Patch{Op: Replace, HTML: newHTML}, path)
+		}
+		return
+	}
+
+	d.attrs(attrs(old), attrs(new), path)
+	if newEl {
+		d.children(children(old), children(new), path)
+	}
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (d *differ) attrs(old, new map[string]string, path []int) {
+	for _, name := range sortedKeys(new) {
+		if v, ok := old[name]; !ok || v != new[name] {
+			d.add(Patch{Op: SetAttr, Name: name, Value: new[name]}, path)
+		}
+	}
+	for _, name := range sortedKeys(old) {
+		if _, ok := new[name]; !ok {
+			d.add(Patch{Op: RemoveAttr, Name: name}, path)
+		}
+	}
+}
+
+func childPath(path []int, i int) []int {
+	return append(path[:len(path):len(path)], i)
+}
+
+func (d *differ) children(old, new []html.Node, path []int) {
+	// match[j] is the index in old of the child matched with new[j], or -1.
+	match := make([]int, len(new))
+	used := make([]bool, len(old))
+	oldKeys := make(map[string]int)
+	var oldUnkeyed []int
+	for i, nd := range old {
+		if key := keyOf(nd); key == "" {
+			oldUnkeyed = append(oldUnkeyed, i)
+		} else if _, ok := oldKeys[key]; !ok {
+			oldKeys[key] = i
+		}
+	}
+	for j, nd := range new {
+		match[j] = -1
+		if key := keyOf(nd); key != "" {
+			if i, ok := oldKeys[key]; ok && !used[i] {
+				match[j], used[i] = i, true
+			}
+		} else if len(oldUnkeyed) > 0 {
+			match[j], used[oldUnkeyed[0]] = oldUnkeyed[0], true
+			oldUnkeyed = oldUnkeyed[1:]
+		}
+	}
+
+	// Remove the unmatched old children, from the last one so that the indexes
+	// of the others are not changed.
+	var cur []int
+	for i := len(old) - 1; i >= 0; i-- {
+		if !used[i] {
+			d.add(Patch{Op: Remove}, childPath(path, i))
+		}
+	}
+	for i := range old {
+		if used[i] {
+			cur = append(cur, i)
+		}
+	}
+
+	for j, nd := range new {
+		if match[j] < 0 {
+			d.add(Patch{Op: Insert, Index: j, HTML: render(nd)}, path)
+			cur = append(cur[:j], append([]int{-1}, cur[j:]...)...)
+			continue
+		}
+		if cur[j] != match[j] {
+			from := j + 1
+			for cur[from] != match[j] {
+				from++
+			}
+			d.add(Patch{Op: Move, From: from, Index: j}, path)
+			copy(cur[j+1:from+1], cur[j:from])
+			cur[j] = match[j]
+		}
+		d.node(old[match[j]], nd, childPath(path, j))
+	}
+}
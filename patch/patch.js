@@ -0,0 +1,89 @@
+// The runtime of github.com/gohtml/html/patch.
+//
+// gohtmlPatch(root, patches) applies the patches generated by patch.Diff to
+// root, the element rendered from the root of the old tree, and returns the
+// new root element, which is different from root if the root is replaced.
+(function (global) {
+  "use strict";
+
+  // Whitespace-only texts are ignored as in patch.Diff.
+  function significant(node) {
+    return node.nodeType === 1 || (node.nodeType === 3 && node.data.trim() !== "");
+  }
+
+  function children(node) {
+    var res = [];
+    for (var c = node.firstChild; c; c = c.nextSibling) {
+      if (significant(c)) {
+        res.push(c);
+      }
+    }
+    return res;
+  }
+
+  function resolve(root, path) {
+    var node = root;
+    for (var i = 0; i < path.length; i++) {
+      node = children(node)[path[i]];
+      if (!node) {
+        throw new Error("gohtmlPatch: invalid path " + path.join("/"));
+      }
+    }
+    return node;
+  }
+
+  function parse(html) {
+    var t = document.createElement("template");
+    t.innerHTML = html;
+    return t.content;
+  }
+
+  function insertAt(parent, index, node) {
+    parent.insertBefore(node, children(parent)[index] || null);
+  }
+
+  function apply(root, patches) {
+    for (var i = 0; i < patches.length; i++) {
+      var p = patches[i];
+      var node = resolve(root, p.path || []);
+      var parent = node.parentNode;
+      switch (p.op) {
+        case "insert":
+          insertAt(node, p.index || 0, parse(p.html || ""));
+          break;
+        case "remove":
+          parent.removeChild(node);
+          break;
+        case "replace":
+          var frag = parse(p.html || "");
+          if (node === root) {
+            root = frag.firstElementChild || root;
+          }
+          parent.replaceChild(frag, node);
+          break;
+        case "move":
+          var child = children(node)[p.from || 0];
+          node.removeChild(child);
+          insertAt(node, p.index || 0, child);
+          break;
+        case "attr":
+          node.setAttribute(p.name, p.value || "");
+          break;
+        case "rmattr":
+          node.removeAttribute(p.name);
+          break;
+        case "text":
+          node.data = p.value || "";
+          break;
+        default:
+          throw new Error("gohtmlPatch: unknown op " + p.op);
+      }
+    }
+    // Texts are merged only after all patches, since the paths of patch.Diff
+    // count the texts of the patches separately until then.
+    root.normalize();
+    return root;
+  }
+
+  global.gohtmlPatch = apply;
+})(typeof window !== "undefined" ? window : this);
@@ -0,0 +1,233 @@
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golangplus/testing/assert"
+
+	. "github.com/gohtml/elements"
+	"github.com/gohtml/html"
+)
+
+// mockNode is a simplified DOM node to apply patches on.
+type mockNode struct {
+	name     string
+	attrs    map[string]string
+	children []*mockNode
+	// The text of a text node, or the HTML of an inserted node.
+	text string
+}
+
+func newMock(nd html.Node) *mockNode {
+	if nd.Type() == TextType {
+		return &mockNode{text: render(nd)}
+	}
+	n, _ := name(nd)
+	m := &mockNode{name: n, attrs: attrs(nd)}
+	for _, child := range children(nd) {
+		m.children = append(m.children, newMock(child))
+	}
+	return m
+}
+
+func (m *mockNode) String() string {
+	if m.name == "" {
+		return m.text
+	}
+	s := "<" + m.name
+	for _, k := range sortedKeys(m.attrs) {
+		s += fmt.Sprintf(` %s="%s"`, k, m.attrs[k])
+	}
+	s += ">"
+	for _, c := range m.children {
+		s += c.String()
+	}
+	return s + "</" + m.name + ">"
+}
+
+func (m *mockNode) resolve(path []int) (parent, node *mockNode) {
+	node = m
+	for _, i := range path {
+		parent, node = node, node.children[i]
+	}
+	return parent, node
+}
+
+func insertChild(children []*mockNode, i int, nd *mockNode) []*mockNode {
+	return append(children[:i], append([]*mockNode{nd}, children[i:]...)...)
+}
+
+func indexOf(children []*mockNode, nd *mockNode) int {
+	for i, c := range children {
+		if c == nd {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *mockNode) apply(patches []Patch) {
+	for _, p := range patches {
+		parent, node := m.resolve(p.Path)
+		switch p.Op {
+		case Insert:
+			node.children = insertChild(node.children, p.Index, &mockNode{text: p.HTML})
+		case Remove:
+			i := indexOf(parent.children, node)
+			parent.children = append(parent.children[:i], parent.children[i+1:]...)
+		case Replace:
+			parent.children[indexOf(parent.children, node)] = &mockNode{text: p.HTML}
+		case Move:
+			child := node.children[p.From]
+			node.children = append(node.children[:p.From], node.children[p.From+1:]...)
+			node.children = insertChild(node.children, p.Index, child)
+		case SetAttr:
+			node.attrs[p.Name] = p.Value
+		case RemoveAttr:
+			delete(node.attrs, p.Name)
+		case SetText:
+			node.text = p.Value
+		}
+	}
+}
+
+func ops(patches []Patch) string {
+	var res []string
+	for _, p := range patches {
+		res = append(res, fmt.Sprintf("%s%v", p.Op, p.Path))
+	}
+	return strings.Join(res, " ")
+}
+
+func TestDiff_same(t *testing.T) {
+	nd := func() html.Node {
+		return html.DIV(html.P(html.T("a")), html.INPUT("text", "q", "")).Attr("id", "x")
+	}
+	assert.Equal(t, "patches", len(Diff(nd(), nd())), 0)
+}
+
+func TestDiff_attrsAndText(t *testing.T) {
+	old := html.DIV(html.P(html.T("Hello")).AddClass("a").Attr("title", "t"))
+	new := html.DIV(html.P(html.T("Hello, "), html.T("world")).AddClass("b"))
+	assert.Equal(t, "patches", Diff(old, new), []Patch{
+		{Op: SetAttr, Path: []int{0}, Name: "class", Value: "b"},
+		{Op: RemoveAttr, Path: []int{0}, Name: "title"},
+		{Op: SetText, Path: []int{0, 0}, Value: "Hello, world"},
+	})
+}
+
+func TestDiff_replace(t *testing.T) {
+	old := html.DIV(html.P(html.T("a")), html.T("b"))
+	new := html.DIV(html.SPAN(html.T("a")), html.B(html.T("b")))
+	assert.Equal(t, "patches", Diff(old, new), []Patch{
+		{Op: Replace, Path: []int{0}, HTML: "<span>a</span>"},
+		{Op: Replace, Path: []int{1}, HTML: "<b>b</b>"},
+	})
+}
+
+func keyedList(keys ...string) *html.Element {
+	ul := html.UL()
+	for _, key := range keys {
+		ul.Child(html.LI(html.T(strings.ToUpper(key))).Attr(KeyAttr, key))
+	}
+	return ul
+}
+
+func TestDiff_keyed(t *testing.T) {
+	old, new := keyedList("a", "b", "c", "d"), keyedList("d", "b", "e", "a")
+	patches := Diff(old, new)
+	assert.Equal(t, "ops", ops(patches), "remove[2] move[] move[] insert[]")
+
+	m := newMock(old)
+	m.apply(patches)
+	assert.Equal(t, "applied", m.String(), newMock(new).String())
+}
+
+func TestDiff_unkeyed(t *testing.T) {
+	old := html.DIV(html.P(html.T("a")), html.P(html.T("b")), html.P(html.T("c")))
+	new := html.DIV(html.P(html.T("a")).Attr("id", "x"), html.P(html.T("c")))
+	patches := Diff(old, new)
+
+	m := newMock(old)
+	m.apply(patches)
+	assert.Equal(t, "applied", m.String(), newMock(new).String())
+}
+
+func TestDiff_whitespace(t *testing.T) {
+	old := html.DIV(html.T("\n"), html.SPAN(), html.T(" "))
+	new := html.DIV(html.SPAN(), html.B())
+	assert.Equal(t, "patches", Diff(old, new), []Patch{
+		{Op: Insert, Path: []int{}, Index: 1, HTML: "<b></b>"},
+	})
+}
+
+func TestPatch_json(t *testing.T) {
+	js, err := json.Marshal(Diff(keyedList("a", "b"), keyedList("b")))
+	assert.NoError(t, err)
+	assert.Equal(t, "json", string(js), `[{"op":"remove","path":[0]}]`)
+}
+
+func TestRuntime(t *testing.T) {
+	assert.True(t, "gohtmlPatch", strings.Contains(Runtime, "global.gohtmlPatch = apply"))
+	assert.False(t, "</script", strings.Contains(strings.ToLower(Runtime), "</script"))
+}
+
+// TestRuntime_apply applies the patches with patch.js on the DOM of
+// testdata/dom.js, which keeps adjacent texts separate until they are
+// normalized as browsers do.
+func TestRuntime_apply(t *testing.T) {
+	node, err := exec.LookPath("node")
+	if err != nil {
+		t.Skip("node is not installed")
+	}
+	type jsCase struct {
+		Old     string  `json:"old"`
+		Patches []Patch `json:"patches"`
+		New     string  `json:"new"`
+	}
+	var cases []jsCase
+	for _, c := range []struct {
+		old, new html.Node
+	}{
+		{html.DIV(html.SPAN(), html.T("c")), html.DIV(html.T("a"), html.B())},
+		{html.DIV(html.T("a"), html.SPAN(), html.T("c")), html.DIV(html.T("a"), html.T("c"))},
+		{html.DIV(html.B(), html.T("b"), html.SPAN()), html.DIV(html.T("a"), html.T("b"), html.SPAN(), html.T("c"))},
+		{html.DIV(html.SPAN(html.T("x")), html.T("y")), html.DIV(html.T("x"), html.SPAN(html.T("y")))},
+		{html.DIV(html.P(html.T("Hello")).AddClass("a")), html.DIV(html.P(html.T("Hello, "), html.T("world")))},
+		{keyedList("a", "b", "c", "d"), keyedList("d", "b", "e", "a")},
+		{html.DIV(html.T("\n"), html.SPAN(), html.T(" ")), html.DIV(html.SPAN(), html.B())},
+	} {
+		cases = append(cases, jsCase{Old: render(c.old), Patches: Diff(c.old, c.new), New: render(c.new)})
+	}
+	js, err := json.Marshal(cases)
+	assert.NoError(t, err)
+
+	cmd := exec.Command(node, filepath.Join("testdata", "dom.js"), "patch.js")
+	cmd.Stdin = bytes.NewReader(js)
+	out, err := cmd.Output()
+	assert.NoError(t, err)
+	var results []struct {
+		Got  string `json:"got"`
+		Want string `json:"want"`
+	}
+	assert.NoError(t, json.Unmarshal(out, &results))
+	assert.Equal(t, "len(results)", len(results), len(cases))
+	for i, r := range results {
+		assert.Equal(t, cases[i].Old+" -> "+cases[i].New, r.Got, r.Want)
+	}
+}
+
+func ExampleDiff() {
+	old := html.UL(html.LI(html.T("Milk")).Attr(KeyAttr, "1"))
+	new := html.UL(html.LI(html.T("Eggs")).Attr(KeyAttr, "2"), html.LI(html.T("Milk")).Attr(KeyAttr, "1"))
+	js, _ := json.Marshal(Diff(old, new))
+	fmt.Println(string(js))
+	// OUTPUT:
+	// [{"op":"insert","path":[],"html":"\u003cli data-key=\"2\"\u003eEggs\u003c/li\u003e"}]
+}
@@ -0,0 +1,203 @@
+// A minimal DOM to run patch.js in node. Adjacent texts are kept as separate
+// nodes until normalize() is called, as in browsers.
+//
+// Usage: node dom.js patch.js < cases.json
+//
+// Each case is {"old": html, "patches": [...], "new": html}. The output is the
+// array of {"got": ..., "want": ...}, the serialized DOMs of old with the
+// patches applied and of new.
+"use strict";
+
+var fs = require("fs");
+var vm = require("vm");
+
+var voidTags = {
+  area: true, base: true, br: true, col: true, embed: true, hr: true, img: true,
+  input: true, link: true, meta: true, param: true, source: true, track: true, wbr: true
+};
+
+function Node(nodeType, name) {
+  this.nodeType = nodeType;
+  this.name = name;
+  this.attrs = {};
+  this.data = "";
+  this.parentNode = null;
+  this.childNodes = [];
+}
+
+Object.defineProperty(Node.prototype, "firstChild", {
+  get: function () { return this.childNodes[0] || null; }
+});
+
+Object.defineProperty(Node.prototype, "nextSibling", {
+  get: function () {
+    if (!this.parentNode) {
+      return null;
+    }
+    var siblings = this.parentNode.childNodes;
+    return siblings[siblings.indexOf(this) + 1] || null;
+  }
+});
+
+Object.defineProperty(Node.prototype, "firstElementChild", {
+  get: function () {
+    for (var i = 0; i < this.childNodes.length; i++) {
+      if (this.childNodes[i].nodeType === 1) {
+        return this.childNodes[i];
+      }
+    }
+    return null;
+  }
+});
+
+// Fragments (nodeType 11) are inserted as their children.
+Node.prototype.insertBefore = function (node, ref) {
+  var nodes = node.nodeType === 11 ? node.childNodes.slice() : [node];
+  nodes.forEach(function (n) {
+    if (n.parentNode) {
+      n.parentNode.removeChild(n);
+    }
+  });
+  var i = ref ? this.childNodes.indexOf(ref) : this.childNodes.length;
+  if (i < 0) {
+    throw new Error("insertBefore: not a child");
+  }
+  var self = this;
+  nodes.forEach(function (n) {
+    n.parentNode = self;
+  });
+  this.childNodes.splice.apply(this.childNodes, [i, 0].concat(nodes));
+};
+
+Node.prototype.removeChild = function (node) {
+  var i = this.childNodes.indexOf(node);
+  if (i < 0) {
+    throw new Error("removeChild: not a child");
+  }
+  this.childNodes.splice(i, 1);
+  node.parentNode = null;
+  return node;
+};
+
+Node.prototype.replaceChild = function (node, old) {
+  this.insertBefore(node, old);
+  this.removeChild(old);
+};
+
+Node.prototype.normalize = function () {
+  var res = [];
+  this.childNodes.forEach(function (c) {
+    if (c.nodeType === 3) {
+      var last = res[res.length - 1];
+      if (last && last.nodeType === 3) {
+        last.data += c.data;
+        c.parentNode = null;
+        return;
+      }
+    } else {
+      c.normalize();
+    }
+    res.push(c);
+  });
+  this.childNodes = res.filter(function (c) {
+    return c.nodeType !== 3 || c.data !== "";
+  });
+};
+
+Node.prototype.setAttribute = function (name, value) {
+  this.attrs[name] = String(value);
+};
+
+Node.prototype.removeAttribute = function (name) {
+  delete this.attrs[name];
+};
+
+function unescape(s) {
+  return s.replace(/&(#x[0-9a-f]+|#[0-9]+|amp|lt|gt|quot|apos);/gi, function (m, e) {
+    switch (e.toLowerCase()) {
+      case "amp": return "&";
+      case "lt": return "<";
+      case "gt": return ">";
+      case "quot": return "\"";
+      case "apos": return "'";
+    }
+    return String.fromCharCode(e[1] === "x" || e[1] === "X" ? parseInt(e.slice(2), 16) : parseInt(e.slice(1), 10));
+  });
+}
+
+// parse parses html written with explicit end tags into a fragment.
+function parse(html) {
+  var frag = new Node(11, "");
+  var cur = frag;
+  var re = /<\/([a-z0-9-]+)>|<([a-z0-9-]+)((?:\s+[^\s=>]+(?:="[^"]*")?)*)\s*>|([^<]+)/gi;
+  var m;
+  while ((m = re.exec(html))) {
+    if (m[1]) {
+      cur = cur.parentNode;
+    } else if (m[2]) {
+      var el = new Node(1, m[2].toLowerCase());
+      var attrRe = /([^\s=>]+)(?:="([^"]*)")?/g, a;
+      while ((a = attrRe.exec(m[3]))) {
+        el.attrs[a[1]] = unescape(a[2] || "");
+      }
+      cur.insertBefore(el, null);
+      if (!voidTags[el.name]) {
+        cur = el;
+      }
+    } else {
+      var t = new Node(3, "");
+      t.data = unescape(m[4]);
+      cur.insertBefore(t, null);
+    }
+  }
+  return frag;
+}
+
+var document = {
+  createElement: function (name) {
+    var el = new Node(1, name);
+    if (name === "template") {
+      Object.defineProperty(el, "innerHTML", {
+        set: function (html) { el.content = parse(html); }
+      });
+    }
+    return el;
+  }
+};
+
+// serialize writes each significant text as a JSON string, so unmerged texts
+// are distinguished from merged ones.
+function serialize(node) {
+  if (node.nodeType === 3) {
+    var s = node.data.trim();
+    return s === "" ? "" : JSON.stringify(s);
+  }
+  var s = "<" + node.name;
+  Object.keys(node.attrs).sort().forEach(function (k) {
+    s += " " + k + "=" + JSON.stringify(node.attrs[k]);
+  });
+  s += ">";
+  node.childNodes.forEach(function (c) {
+    s += serialize(c);
+  });
+  return s + "</" + node.name + ">";
+}
+
+var context = vm.createContext({ document: document });
+vm.runInContext(fs.readFileSync(process.argv[2], "utf8"), context);
+
+var cases = JSON.parse(fs.readFileSync(0, "utf8"));
+var results = cases.map(function (c) {
+  var got;
+  try {
+    var frag = parse(c.old);
+    var root = frag.firstElementChild;
+    got = serialize(context.gohtmlPatch(root, c.patches || []));
+  } catch (e) {
+    got = "error: " + e.message;
+  }
+  var want = parse(c.new).firstElementChild;
+  want.normalize();
+  return { got: got, want: serialize(want) };
+});
+process.stdout.write(JSON.stringify(results));
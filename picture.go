@@ -0,0 +1,124 @@
+package html
+
+import (
+	"strconv"
+	"strings"
+
+	. "github.com/gohtml/elements"
+)
+
+// PICTURE creates a picture element. children are usually SOURCEs followed by
+// an IMG.
+// http://www.w3.org/TR/html51/semantics-embedded-content.html#the-picture-element
+func PICTURE(children ...Node) *Element {
+	return (&Element{
+		Void: Void{tagType: PICTURETag},
+	}).Child(children...)
+}
+
+// SOURCE creates a source element for a PICTURE. tp is the MIME type, e.g.
+// "image/webp", and is ignored if empty.
+func SOURCE(srcset, tp string) *Void {
+	return (&Void{
+		tagType: SOURCETag,
+	}).Attr("srcset", srcset).AttrIfNotEmpty("type", tp)
+}
+
+// ImageCandidate is a candidate of a srcset.
+type ImageCandidate struct {
+	URL URL
+	// The width of the image in pixels. Used as the width descriptor, e.g. "640w".
+	Width int
+}
+
+// srcsetURLEscaper escapes characters that separate candidates and descriptors.
+var srcsetURLEscaper = strings.NewReplacer(",", "%2C", " ", "%20", "\t", "%09", "\n", "%0A")
+
+// Srcset returns a srcset of the candidates. Commas and spaces in URLs are
+// percent-encoded so that they are not parsed as separators.
+func Srcset(candidates ...ImageCandidate) string {
+	parts := make([]string, len(candidates))
+	for i, c := range candidates {
+		parts[i] = srcsetURLEscaper.Replace(string(c.URL))
+		if c.Width > 0 {
+			parts[i] += " " + strconv.Itoa(c.Width) + "w"
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ImageFormat is a format of a ResponsiveImage.
+type ImageFormat struct {
+	// The MIME type, e.g. "image/avif".
+	Type string
+	// Returns the URL of the image of the format at a width.
+	URL func(width int) URL
+}
+
+// ResponsiveImage describes an image available in several widths and formats.
+type ResponsiveImage struct {
+	// Formats in the order of preference, e.g. AVIF, WebP and JPEG. The last one
+	// is the fallback used by the IMG and should be supported by all browsers.
+	Formats []ImageFormat
+	// The available widths in pixels.
+	Widths []int
+	// The sizes attribute, e.g. "(max-width: 600px) 100vw, 50vw".
+	Sizes string
+	Alt   string
+	// The intrinsic size of the image, exported to avoid layout shift. Ignored
+	// if not positive.
+	Width, Height int
+	// If true, the image is loaded eagerly, e.g. for images above the fold.
+	Eager bool
+}
+
+// Element creates a PICTURE element with a SOURCE for each format except the
+// last one, and an IMG of the last one with the largest width as src.
+func (ri *ResponsiveImage) Element() *Element {
+	pic := PICTURE()
+	if len(ri.Formats) == 0 {
+		return pic
+	}
+
+	srcset := func(f ImageFormat) string {
+		cands := make([]ImageCandidate, len(ri.Widths))
+		for i, w := range ri.Widths {
+			cands[i] = ImageCandidate{URL: f.URL(w), Width: w}
+		}
+		return Srcset(cands...)
+	}
+
+	last := len(ri.Formats) - 1
+	for _, f := range ri.Formats[:last] {
+		pic.Child(SOURCE(srcset(f), f.Type).AttrIfNotEmpty("sizes", ri.Sizes))
+	}
+
+	fallback := ri.Formats[last]
+	maxWidth := 0
+	for _, w := range ri.Widths {
+		if w > maxWidth {
+			maxWidth = w
+		}
+	}
+	img := IMG(fallback.URL(maxWidth), "").Attr("alt", ri.Alt).
+		Attr("srcset", srcset(fallback)).AttrIfNotEmpty("sizes", ri.Sizes)
+	if ri.Width > 0 && ri.Height > 0 {
+		img.Attr("width", strconv.Itoa(ri.Width)).Attr("height", strconv.Itoa(ri.Height))
+	}
+	if !ri.Eager {
+		img.Attr("loading", "lazy")
+	}
+	img.Attr("decoding", "async")
+
+	return pic.Child(img)
+}
+
+// Implementation of Node interface
+func (ri *ResponsiveImage) Type() TagType {
+	return PICTURETag
+}
+
+// Implementation of Node interface
+func (ri *ResponsiveImage) WriteTo(b Writer, opt RenderOptions, parent *Element, childIndex int) {
+	ri.Element().WriteTo(b, opt, parent, childIndex)
+}
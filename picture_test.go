@@ -0,0 +1,36 @@
+package html
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golangplus/testing/assert"
+
+	. "github.com/gohtml/elements"
+)
+
+func TestSrcset(t *testing.T) {
+	assert.Equal(t, "srcset", Srcset(
+		ImageCandidate{URL: "/img/a,b c.jpg", Width: 320},
+		ImageCandidate{URL: "/img/big.jpg"},
+	), "/img/a%2Cb%20c.jpg 320w, /img/big.jpg")
+}
+
+func ExampleResponsiveImage() {
+	format := func(ext, tp string) ImageFormat {
+		return ImageFormat{Type: tp, URL: func(w int) URL {
+			return URL(fmt.Sprintf("/img/cat-%d.%s", w, ext))
+		}}
+	}
+	img := &ResponsiveImage{
+		Formats: []ImageFormat{format("avif", "image/avif"), format("webp", "image/webp"), format("jpg", "")},
+		Widths:  []int{480, 960},
+		Sizes:   "(max-width: 600px) 100vw, 50vw",
+		Alt:     "A cat",
+		Width:   960,
+		Height:  640,
+	}
+	fmt.Println(NodeToHTMLNode(DIV(img), RenderOptions{SortAttr: true}))
+	// OUTPUT:
+	// <div><picture><source sizes="(max-width: 600px) 100vw, 50vw" srcset="/img/cat-480.avif 480w, /img/cat-960.avif 960w" type="image/avif"><source sizes="(max-width: 600px) 100vw, 50vw" srcset="/img/cat-480.webp 480w, /img/cat-960.webp 960w" type="image/webp"><img alt="A cat" decoding="async" height="640" loading="lazy" sizes="(max-width: 600px) 100vw, 50vw" src="/img/cat-960.jpg" srcset="/img/cat-480.jpg 480w, /img/cat-960.jpg 960w" width="960"></picture></div>
+}
@@ -0,0 +1,186 @@
+// Package schema defines the types of common schema.org structured data.
+// Values of these types are marshaled as JSON-LD with the "@context" and "@type"
+// properties, and can be embedded into a page by html.JSONLD.
+//
+// Dates are strings in ISO 8601 format, e.g. "2015-06-04" or "2015-06-04T08:00:00+08:00".
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+const context = "https://schema.org"
+
+// marshal marshals v as a JSON object with the "@context" and "@type" properties.
+func marshal(tp string, v interface{}) ([]byte, error) {
+	js, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	b.WriteString(`{"@context":"` + context + `","@type":"` + tp + `"`)
+	if len(js) > 2 {
+		b.WriteByte(',')
+	}
+	b.Write(js[1:])
+	return b.Bytes(), nil
+}
+
+// Person is https://schema.org/Person
+type Person struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+func (p Person) MarshalJSON() ([]byte, error) {
+	type plain Person
+	return marshal("Person", plain(p))
+}
+
+// Organization is https://schema.org/Organization
+type Organization struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+	Logo string `json:"logo,omitempty"`
+	// URLs of the profiles of the organization, e.g. on social networks.
+	SameAs []string `json:"sameAs,omitempty"`
+}
+
+func (o Organization) MarshalJSON() ([]byte, error) {
+	type plain Organization
+	return marshal("Organization", plain(o))
+}
+
+// Article is https://schema.org/Article
+type Article struct {
+	Headline         string        `json:"headline"`
+	Description      string        `json:"description,omitempty"`
+	Image            []string      `json:"image,omitempty"`
+	DatePublished    string        `json:"datePublished,omitempty"`
+	DateModified     string        `json:"dateModified,omitempty"`
+	Author           []Person      `json:"author,omitempty"`
+	Publisher        *Organization `json:"publisher,omitempty"`
+	MainEntityOfPage string        `json:"mainEntityOfPage,omitempty"`
+}
+
+func (a Article) MarshalJSON() ([]byte, error) {
+	type plain Article
+	return marshal("Article", plain(a))
+}
+
+// Brand is https://schema.org/Brand
+type Brand struct {
+	Name string `json:"name"`
+}
+
+func (b Brand) MarshalJSON() ([]byte, error) {
+	type plain Brand
+	return marshal("Brand", plain(b))
+}
+
+// Offer is https://schema.org/Offer
+type Offer struct {
+	// The price as a decimal string, e.g. "19.99".
+	Price string `json:"price"`
+	// ISO 4217 currency code, e.g. "USD".
+	PriceCurrency string `json:"priceCurrency"`
+	// e.g. "https://schema.org/InStock"
+	Availability string `json:"availability,omitempty"`
+	URL          string `json:"url,omitempty"`
+}
+
+func (o Offer) MarshalJSON() ([]byte, error) {
+	type plain Offer
+	return marshal("Offer", plain(o))
+}
+
+// AggregateRating is https://schema.org/AggregateRating
+type AggregateRating struct {
+	RatingValue float64 `json:"ratingValue"`
+	ReviewCount int     `json:"reviewCount"`
+}
+
+func (r AggregateRating) MarshalJSON() ([]byte, error) {
+	type plain AggregateRating
+	return marshal("AggregateRating", plain(r))
+}
+
+// Product is https://schema.org/Product
+type Product struct {
+	Name            string           `json:"name"`
+	Description     string           `json:"description,omitempty"`
+	Image           []string         `json:"image,omitempty"`
+	SKU             string           `json:"sku,omitempty"`
+	Brand           *Brand           `json:"brand,omitempty"`
+	Offers          []Offer          `json:"offers,omitempty"`
+	AggregateRating *AggregateRating `json:"aggregateRating,omitempty"`
+}
+
+func (p Product) MarshalJSON() ([]byte, error) {
+	type plain Product
+	return marshal("Product", plain(p))
+}
+
+// ListItem is https://schema.org/ListItem
+type ListItem struct {
+	// Position starts from 1. If zero, it is filled in by BreadcrumbList.
+	Position int    `json:"position"`
+	Name     string `json:"name"`
+	// The URL of the item. Usually omitted for the last item in a breadcrumb.
+	Item string `json:"item,omitempty"`
+}
+
+func (li ListItem) MarshalJSON() ([]byte, error) {
+	type plain ListItem
+	return marshal("ListItem", plain(li))
+}
+
+// BreadcrumbList is https://schema.org/BreadcrumbList
+type BreadcrumbList struct {
+	ItemListElement []ListItem `json:"itemListElement"`
+}
+
+func (bl BreadcrumbList) MarshalJSON() ([]byte, error) {
+	type plain BreadcrumbList
+	items := make([]ListItem, len(bl.ItemListElement))
+	for i, item := range bl.ItemListElement {
+		if item.Position == 0 {
+			item.Position = i + 1
+		}
+		items[i] = item
+	}
+	return marshal("BreadcrumbList", plain{ItemListElement: items})
+}
+
+// Answer is https://schema.org/Answer
+type Answer struct {
+	Text string `json:"text"`
+}
+
+func (a Answer) MarshalJSON() ([]byte, error) {
+	type plain Answer
+	return marshal("Answer", plain(a))
+}
+
+// Question is https://schema.org/Question
+type Question struct {
+	Name           string `json:"name"`
+	AcceptedAnswer Answer `json:"acceptedAnswer"`
+}
+
+func (q Question) MarshalJSON() ([]byte, error) {
+	type plain Question
+	return marshal("Question", plain(q))
+}
+
+// FAQPage is https://schema.org/FAQPage
+type FAQPage struct {
+	MainEntity []Question `json:"mainEntity"`
+}
+
+func (f FAQPage) MarshalJSON() ([]byte, error) {
+	type plain FAQPage
+	return marshal("FAQPage", plain(f))
+}
@@ -0,0 +1,34 @@
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/golangplus/testing/assert"
+)
+
+func TestBreadcrumbList(t *testing.T) {
+	js, err := json.Marshal(BreadcrumbList{
+		ItemListElement: []ListItem{
+			{Name: "Books", Item: "https://example.com/books"},
+			{Name: "Go"},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "js", string(js), `{"@context":"https://schema.org","@type":"BreadcrumbList","itemListElement":[`+
+		`{"@context":"https://schema.org","@type":"ListItem","position":1,"name":"Books","item":"https://example.com/books"},`+
+		`{"@context":"https://schema.org","@type":"ListItem","position":2,"name":"Go"}]}`)
+}
+
+func TestFAQPage(t *testing.T) {
+	js, err := json.Marshal(FAQPage{
+		MainEntity: []Question{{
+			Name:           "Why?",
+			AcceptedAnswer: Answer{Text: "Because."},
+		}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "js", string(js), `{"@context":"https://schema.org","@type":"FAQPage","mainEntity":[`+
+		`{"@context":"https://schema.org","@type":"Question","name":"Why?","acceptedAnswer":`+
+		`{"@context":"https://schema.org","@type":"Answer","text":"Because."}}]}`)
+}
@@ -0,0 +1,350 @@
+package html
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/gohtml/elements"
+)
+
+// ScopedStyle is the CSS of a component scoped by a generated class name.
+// Create one by StyleSet.Scope and add the class to the root element of the
+// component by Apply or AddClass(ss.Class()).
+type ScopedStyle struct {
+	class string
+	// The hash part of the class, identifying the style in file names.
+	hash string
+	css  string
+}
+
+// Class returns the generated class name.
+func (ss *ScopedStyle) Class() string {
+	return ss.class
+}
+
+// CSS returns the scoped CSS.
+func (ss *ScopedStyle) CSS() string {
+	return ss.css
+}
+
+// Apply adds the class to the element.
+func (ss *ScopedStyle) Apply(e *Element) *Element {
+	return e.AddClass(ss.class)
+}
+
+// StyleSet collects the ScopedStyles of components. An Html using a StyleSet,
+// see Html.UseStyles, includes the CSS of the styles used in its tree when it
+// is rendered.
+//
+// A StyleSet is safe for concurrent use.
+type StyleSet struct {
+	mu      sync.RWMutex
+	byClass map[string]*ScopedStyle
+	byHash  map[string]*ScopedStyle
+}
+
+// NewStyleSet returns an empty StyleSet.
+func NewStyleSet() *StyleSet {
+	return &StyleSet{
+		byClass: make(map[string]*ScopedStyle),
+		byHash:  make(map[string]*ScopedStyle),
+	}
+}
+
+func shortHash(s string) string {
+	sum := sha1.Sum([]byte(s))
+	return hex.EncodeToString(sum[:4])
+}
+
+// Scope registers the CSS of a component named name and returns the scoped
+// style. Selectors in css are scoped by the generated class name: "&" is
+// replaced with the class selector, and selectors without "&" are prefixed by
+// it, e.g. with a class "card-1a2b3c4d",
+//
+//	& { padding: 0 } .title { font-weight: bold }
+//
+// becomes
+//
+//	.card-1a2b3c4d { padding: 0 } .card-1a2b3c4d .title { font-weight: bold }
+//
+// Rules in @media, @supports, @container and @layer blocks are scoped too.
+//
+// Scoping the same name and css again returns the registered style.
+func (s *StyleSet) Scope(name, css string) *ScopedStyle {
+	hash := shortHash(name + "\x00" + css)
+	class := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' {
+			return r
+		}
+		return '-'
+	}, name) + "-" + hash
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ss, ok := s.byClass[class]; ok {
+		return ss
+	}
+	ss := &ScopedStyle{
+		class: class,
+		hash:  hash,
+		css:   scopeCSS(css, "."+class),
+	}
+	s.byClass[class] = ss
+	s.byHash[hash] = ss
+	return ss
+}
+
+// collect puts the ScopedStyles of the classes of nd into used.
+func (s *StyleSet) collect(nd Node, used map[*ScopedStyle]bool) map[*ScopedStyle]bool {
+	var classes htmlNodeSet
+	if v := voidOf(nd); v != nil {
+		classes = v.classes
+	} else if f, ok := nd.(*Foreign); ok {
+		classes = f.classes
+	}
+	if len(classes) == 0 {
+		return used
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, cls := range classes {
+		if ss, ok := s.byClass[string(cls)]; ok {
+			if used == nil {
+				used = make(map[*ScopedStyle]bool)
+			}
+			used[ss] = true
+		}
+	}
+	return used
+}
+
+// node returns the STYLE or LINK node for the used styles. Styles are
+// ordered by their classes so that the result doesn't depend on the order they
+// were scoped.
+func (s *StyleSet) node(used map[*ScopedStyle]bool, prefix URL) Node {
+	styles := make([]*ScopedStyle, 0, len(used))
+	for ss := range used {
+		styles = append(styles, ss)
+	}
+	sort.Slice(styles, func(i, j int) bool {
+		return styles[i].class < styles[j].class
+	})
+
+	if prefix == "" {
+		return STYLE(bundleCSS(styles))
+	}
+
+	hashes := make([]string, len(styles))
+	for i, ss := range styles {
+		hashes[i] = ss.hash
+	}
+	file := "styles-" + strings.Join(hashes, "-") + ".css"
+	return LINK(prefix+URL(file), "stylesheet").Attr("type", "text/css")
+}
+
+func bundleCSS(styles []*ScopedStyle) string {
+	var b strings.Builder
+	for _, ss := range styles {
+		b.WriteString(ss.css)
+	}
+	return b.String()
+}
+
+// ServeHTTP serves the CSS files linked by Html using the set with a prefix.
+// The last element of the URL path is the file name, which contains the
+// hashes of the styles in it. Files are not stored but built from the styles,
+// so any instance of a server with the same styles scoped can serve them, and
+// they are cached by clients for long.
+func (s *StyleSet) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	file := path.Base(r.URL.Path)
+	if !strings.HasPrefix(file, "styles-") || !strings.HasSuffix(file, ".css") {
+		http.NotFound(w, r)
+		return
+	}
+
+	hashes := strings.Split(strings.TrimSuffix(strings.TrimPrefix(file, "styles-"), ".css"), "-")
+	styles := make([]*ScopedStyle, len(hashes))
+	s.mu.RLock()
+	for i, hash := range hashes {
+		styles[i] = s.byHash[hash]
+	}
+	s.mu.RUnlock()
+	for _, ss := range styles {
+		if ss == nil {
+			http.NotFound(w, r)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/css; charset=utf-8")
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	http.ServeContent(w, r, "", time.Time{}, strings.NewReader(bundleCSS(styles)))
+}
+
+// skipCSSString returns the index after the string or comment starting at i,
+// or i if there is none.
+func skipCSSString(css string, i int) int {
+	switch {
+	case css[i] == '"' || css[i] == '\'':
+		q := css[i]
+		for j := i + 1; j < len(css); j++ {
+			switch css[j] {
+			case '\\':
+				j++
+			case q:
+				return j + 1
+			}
+		}
+		return len(css)
+
+	case strings.HasPrefix(css[i:], "/*"):
+		if p := strings.Index(css[i+2:], "*/"); p >= 0 {
+			return i + 2 + p + 2
+		}
+		return len(css)
+	}
+	return i
+}
+
+// blockEnd returns the index after the '}' matching the '{' at i, and whether
+// the '}' is found. It returns len(css) if the block is not closed.
+func blockEnd(css string, i int) (int, bool) {
+	depth := 0
+	for i < len(css) {
+		if j := skipCSSString(css, i); j > i {
+			i = j
+			continue
+		}
+		switch css[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i + 1, true
+			}
+		}
+		i++
+	}
+	return len(css), false
+}
+
+// splitSelectors splits a selector list at the commas which are not in
+// parentheses, brackets or strings, e.g. in ":is(a, b)" or "[x='a,b']".
+func splitSelectors(prelude string) []string {
+	var (
+		sels  []string
+		depth int
+		start int
+	)
+	for i := 0; i < len(prelude); {
+		if j := skipCSSString(prelude, i); j > i {
+			i = j
+			continue
+		}
+		switch prelude[i] {
+		case '\\':
+			i++
+		case '(', '[':
+			depth++
+		case ')', ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				sels = append(sels, prelude[start:i])
+				start = i + 1
+			}
+		}
+		i++
+	}
+	return append(sels, prelude[start:])
+}
+
+func scopeSelectors(prelude, scope string) string {
+	sels := splitSelectors(prelude)
+	for i, sel := range sels {
+		sel = strings.TrimSpace(sel)
+		if strings.Contains(sel, "&") {
+			sels[i] = strings.Replace(sel, "&", scope, -1)
+		} else {
+			sels[i] = scope + " " + sel
+		}
+	}
+	return strings.Join(sels, ", ")
+}
+
+// stripCSSComments removes comments in css.
+func stripCSSComments(css string) string {
+	var b strings.Builder
+	for i := 0; i < len(css); {
+		j := skipCSSString(css, i)
+		switch {
+		case j == i:
+			b.WriteByte(css[i])
+			i++
+		case css[i] == '/':
+			i = j
+		default:
+			b.WriteString(css[i:j])
+			i = j
+		}
+	}
+	return b.String()
+}
+
+// scopeCSS scopes the selectors of the rules in css.
+func scopeCSS(css, scope string) string {
+	return scopeRules(stripCSSComments(css), scope)
+}
+
+// scopeRules scopes the selectors of the rules in css without comments. Rules
+// are separated by newlines in the result.
+func scopeRules(css, scope string) string {
+	var b strings.Builder
+	start := 0
+	for i := 0; i < len(css); {
+		if j := skipCSSString(css, i); j > i {
+			i = j
+			continue
+		}
+
+		switch css[i] {
+		case ';':
+			// e.g. @import
+			b.WriteString(strings.TrimSpace(css[start:i+1]) + "\n")
+			i++
+			start = i
+
+		case '{':
+			prelude := strings.TrimSpace(css[start:i])
+			end, closed := blockEnd(css, i)
+			block := css[i+1 : end]
+			if closed {
+				block = css[i+1 : end-1]
+			}
+			switch {
+			case strings.HasPrefix(prelude, "@media"), strings.HasPrefix(prelude, "@supports"),
+				strings.HasPrefix(prelude, "@container"), strings.HasPrefix(prelude, "@layer"):
+				b.WriteString(prelude + " {\n" + scopeRules(block, scope) + "}\n")
+			case strings.HasPrefix(prelude, "@"):
+				// e.g. @font-face and @keyframes
+				b.WriteString(prelude + " {" + block + "}\n")
+			default:
+				b.WriteString(scopeSelectors(prelude, scope) + " {" + block + "}\n")
+			}
+			i = end
+			start = i
+
+		default:
+			i++
+		}
+	}
+	return b.String()
+}
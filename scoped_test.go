@@ -0,0 +1,104 @@
+package html
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golangplus/testing/assert"
+)
+
+func TestScopeCSS(t *testing.T) {
+	assert.Equal(t, "css", scopeCSS(`
+@import "base.css";
+& { padding: 0 }
+.title, &:hover > a { content: "}" }
+:is(h1, h2), [data-x="a,b"] { margin: 0 }
+/* { */
+@media (max-width: 600px) { & .title { display: none } }
+@keyframes spin { from { transform: rotate(0) } }
+`, ".c"), `@import "base.css";
+.c { padding: 0 }
+.c .title, .c:hover > a { content: "}" }
+.c :is(h1, h2), .c [data-x="a,b"] { margin: 0 }
+@media (max-width: 600px) {
+.c .title { display: none }
+}
+@keyframes spin { from { transform: rotate(0) } }
+`)
+}
+
+func TestScopeCSS_unterminated(t *testing.T) {
+	assert.Equal(t, "empty", scopeCSS("a {", ".c"), ".c a {}\n")
+	assert.Equal(t, "block", scopeCSS("a { color: red", ".c"), ".c a { color: red}\n")
+	assert.Equal(t, "nested", scopeCSS("@media print { a { color: red }", ".c"), "@media print {\n.c a { color: red }\n}\n")
+}
+
+func TestStyleSet(t *testing.T) {
+	set := NewStyleSet()
+	card := set.Scope("card", "& { padding: 0 }")
+	assert.True(t, "same", set.Scope("card", "& { padding: 0 }") == card)
+	set.Scope("unused", "& { color: red }")
+
+	h := HTML("").UseStyles(set, "")
+	h.Body().Child(card.Apply(DIV(T("Hi"))))
+
+	cls := card.Class()
+	assert.ValueShould(t, "class", cls, strings.HasPrefix(cls, "card-") && len(cls) == len("card-")+8, "is invalid")
+	assert.StringEqual(t, "html", NodeToHTMLNode(h, DefaultOptions), `<!DOCTYPE html>
+<meta charset="utf-8"><style>.`+cls+` { padding: 0 }
+</style><div class="`+cls+`">Hi</div>`)
+
+	h.UseStyles(set, "/css/")
+	out := string(NodeToHTMLNode(h, RenderOptions{SortAttr: true}))
+	p := strings.Index(out, `href="/css/`)
+	assert.ValueShould(t, "html", out, p >= 0, "contains no link")
+	file := out[p+len(`href="/css/`):]
+	file = file[:strings.IndexByte(file, '"')]
+
+	w := httptest.NewRecorder()
+	set.ServeHTTP(w, httptest.NewRequest("GET", "/css/"+file, nil))
+	assert.Equal(t, "code", w.Code, http.StatusOK)
+	assert.Equal(t, "body", w.Body.String(), "."+cls+" { padding: 0 }\n")
+
+	// Another instance with the same styles serves the file without rendering.
+	other := NewStyleSet()
+	other.Scope("card", "& { padding: 0 }")
+	w = httptest.NewRecorder()
+	other.ServeHTTP(w, httptest.NewRequest("GET", "/css/"+file, nil))
+	assert.Equal(t, "code", w.Code, http.StatusOK)
+	assert.Equal(t, "body", w.Body.String(), "."+cls+" { padding: 0 }\n")
+
+	for _, f := range []string{"styles-0.css", "x.css", "styles-.css"} {
+		w = httptest.NewRecorder()
+		set.ServeHTTP(w, httptest.NewRequest("GET", "/css/"+f, nil))
+		assert.Equal(t, f, w.Code, http.StatusNotFound)
+	}
+}
+
+func TestStyleSet_order(t *testing.T) {
+	set := NewStyleSet()
+	b := set.Scope("b", "& { color: blue }")
+	a := set.Scope("a", "& { color: red }")
+
+	h := HTML("").UseStyles(set, "/css/")
+	h.Body().Child(b.Apply(DIV()), a.Apply(DIV()))
+	out := string(NodeToHTMLNode(h, DefaultOptions))
+	file := "styles-" + strings.TrimPrefix(a.Class(), "a-") + "-" + strings.TrimPrefix(b.Class(), "b-") + ".css"
+	assert.ValueShould(t, "html", out, strings.Contains(out, `href="/css/`+file+`"`), "doesn't link "+file)
+
+	w := httptest.NewRecorder()
+	set.ServeHTTP(w, httptest.NewRequest("GET", "/css/"+file, nil))
+	assert.Equal(t, "body", w.Body.String(), a.CSS()+b.CSS())
+}
+
+func ExampleStyleSet_Scope() {
+	set := NewStyleSet()
+	ss := set.Scope("note", "& { border: 1px solid } .title { font-weight: bold }")
+	fmt.Println(strings.Replace(ss.CSS(), ss.Class(), "note-XXX", -1))
+	// OUTPUT:
+	// .note-XXX { border: 1px solid }
+	// .note-XXX .title { font-weight: bold }
+}
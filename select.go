@@ -0,0 +1,484 @@
+package html
+
+import (
+	"fmt"
+	"strings"
+
+	. "github.com/gohtml/elements"
+)
+
+// ChildNodes returns the children of nd as they are rendered. The head of an
+// Html includes the contributions of the nodes in the tree, the entries of a
+// HeadSet and the nodes of Nodes are spliced into their parents, and nodes
+// with an Element method, e.g. Table, and StaticNodes are replaced with their
+// elements and sources. It returns nil if nd has no children.
+func ChildNodes(nd Node) []Node {
+	var children []Node
+	switch p := expandNode(nd).(type) {
+	case *Html:
+		children = p.rendered(&RenderOptions{}).children
+	case interface{ Children() []Node }:
+		children = p.Children()
+	default:
+		return nil
+	}
+
+	var res []Node
+	for _, child := range children {
+		switch child.(type) {
+		case Nodes, *HeadSet:
+			res = append(res, ChildNodes(child)...)
+			continue
+		}
+		res = append(res, expandNode(child))
+	}
+	return res
+}
+
+// expandNode returns the source of a StaticNode, the element of nd if nd has an
+// Element method, or nd itself otherwise.
+func expandNode(nd Node) Node {
+	if s, ok := nd.(*StaticNode); ok {
+		return expandNode(s.src)
+	}
+	if p, ok := nd.(interface{ Element() *Element }); ok {
+		return p.Element()
+	}
+	return nd
+}
+
+type attrSelector struct {
+	name string
+	// One of "", "=", "~=", "|=", "^=", "$=" and "*=". Empty for [name].
+	op    string
+	value string
+}
+
+// compoundSelector is a sequence of simple selectors, e.g. "li.active".
+type compoundSelector struct {
+	// Empty for any element.
+	tag     string
+	attrs   []attrSelector
+	pseudos []string
+	// The combinator with the previous compound selector: ' ', '>', '+' or '~'.
+	// Zero for the first one.
+	combinator byte
+}
+
+// Selector is a parsed CSS selector.
+//
+// Supported are type, universal, id, class and attribute selectors, the
+// :first-child, :last-child, :only-child and :empty pseudo-classes, all four
+// combinators and selector lists. Type selectors are case-insensitive.
+type Selector struct {
+	src  string
+	alts [][]compoundSelector
+}
+
+// ParseSelector parses a CSS selector, e.g. "nav li.active > a[href^='/']".
+func ParseSelector(s string) (*Selector, error) {
+	p := selectorParser{s: s}
+	sel := &Selector{src: s}
+	for {
+		cs, err := p.complex()
+		if err != nil {
+			return nil, fmt.Errorf("selector %q: %v", s, err)
+		}
+		sel.alts = append(sel.alts, cs)
+
+		p.skipSpaces()
+		if p.eof() {
+			return sel, nil
+		}
+		if p.s[p.pos] != ',' {
+			return nil, fmt.Errorf("selector %q: unexpected %q at %d", s, p.s[p.pos], p.pos)
+		}
+		p.pos++
+	}
+}
+
+// MustParseSelector is same as ParseSelector but panics on errors.
+func MustParseSelector(s string) *Selector {
+	sel, err := ParseSelector(s)
+	if err != nil {
+		panic(err)
+	}
+	return sel
+}
+
+func (s *Selector) String() string {
+	return s.src
+}
+
+// selPos is the position of a node in the tree.
+type selPos struct {
+	node     Node
+	siblings []Node
+	index    int
+}
+
+// Select returns the nodes in the tree rooted at root, including root itself,
+// that match the selector, in document order.
+func (s *Selector) Select(root Node) []Node {
+	var res []Node
+	s.walk([]selPos{{node: expandNode(root)}}, func(nd Node) bool {
+		res = append(res, nd)
+		return true
+	})
+	return res
+}
+
+// First returns the first node in document order that matches the selector,
+// or nil if there is none.
+func (s *Selector) First(root Node) Node {
+	var res Node
+	s.walk([]selPos{{node: expandNode(root)}}, func(nd Node) bool {
+		res = nd
+		return false
+	})
+	return res
+}
+
+// Matches returns true if the root of the tree matches the selector.
+func (s *Selector) Matches(nd Node) bool {
+	return s.matches([]selPos{{node: expandNode(nd)}})
+}
+
+// walk calls fn for each matching node until fn returns false. path ends with
+// the current node.
+func (s *Selector) walk(path []selPos, fn func(nd Node) bool) bool {
+	cur := path[len(path)-1].node
+	if s.matches(path) && !fn(cur) {
+		return false
+	}
+
+	children := ChildNodes(cur)
+	for i, child := range children {
+		if !s.walk(append(path, selPos{node: child, siblings: children, index: i}), fn) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Selector) matches(path []selPos) bool {
+	for _, cs := range s.alts {
+		if matchComplex(cs, len(cs)-1, path) {
+			return true
+		}
+	}
+	return false
+}
+
+func isElementNode(nd Node) bool {
+	return nd.Type() != TextType && nd.Type() != RawType
+}
+
+func matchComplex(cs []compoundSelector, k int, path []selPos) bool {
+	last := len(path) - 1
+	if !cs[k].matches(path[last]) {
+		return false
+	}
+	if k == 0 {
+		return true
+	}
+
+	switch cs[k].combinator {
+	case '>':
+		return last > 0 && matchComplex(cs, k-1, path[:last])
+
+	case ' ':
+		for j := last - 1; j >= 0; j-- {
+			if matchComplex(cs, k-1, path[:j+1]) {
+				return true
+			}
+		}
+
+	case '+', '~':
+		pos := path[last]
+		for i := pos.index - 1; i >= 0; i-- {
+			sib := pos.siblings[i]
+			if !isElementNode(sib) {
+				continue
+			}
+			sibPath := append(path[:last:last], selPos{node: sib, siblings: pos.siblings, index: i})
+			if matchComplex(cs, k-1, sibPath) {
+				return true
+			}
+			if cs[k].combinator == '+' {
+				break
+			}
+		}
+	}
+	return false
+}
+
+func (c *compoundSelector) matches(pos selPos) bool {
+	nd := pos.node
+	if !isElementNode(nd) {
+		return false
+	}
+	if c.tag != "" {
+		named, ok := nd.(interface{ Name() HTMLNode })
+		if !ok || !strings.EqualFold(string(named.Name()), c.tag) {
+			return false
+		}
+	}
+
+	for _, a := range c.attrs {
+		if !a.matches(nd) {
+			return false
+		}
+	}
+
+	for _, pseudo := range c.pseudos {
+		// The root has no siblings.
+		before, after := pos.siblings, pos.siblings
+		if before != nil {
+			before, after = before[:pos.index], after[pos.index+1:]
+		}
+
+		var ok bool
+		switch pseudo {
+		case "first-child":
+			ok = isFirstElement(before)
+		case "last-child":
+			ok = isFirstElement(after)
+		case "only-child":
+			ok = isFirstElement(before) && isFirstElement(after)
+		case "empty":
+			ok = len(ChildNodes(nd)) == 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// isFirstElement returns true if there is no element in siblings.
+func isFirstElement(siblings []Node) bool {
+	for _, sib := range siblings {
+		if isElementNode(sib) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a *attrSelector) matches(nd Node) bool {
+	an, ok := nd.(interface {
+		GetAttr(name string) (string, bool)
+	})
+	if !ok {
+		return false
+	}
+	v, ok := an.GetAttr(a.name)
+	if !ok {
+		return false
+	}
+
+	switch a.op {
+	case "=":
+		return v == a.value
+	case "~=":
+		for _, f := range strings.Fields(v) {
+			if f == a.value {
+				return true
+			}
+		}
+		return false
+	case "|=":
+		return v == a.value || strings.HasPrefix(v, a.value+"-")
+	case "^=":
+		return a.value != "" && strings.HasPrefix(v, a.value)
+	case "$=":
+		return a.value != "" && strings.HasSuffix(v, a.value)
+	case "*=":
+		return a.value != "" && strings.Contains(v, a.value)
+	}
+	return true
+}
+
+type selectorParser struct {
+	s   string
+	pos int
+}
+
+func (p *selectorParser) eof() bool {
+	return p.pos >= len(p.s)
+}
+
+func (p *selectorParser) skipSpaces() bool {
+	start := p.pos
+	for !p.eof() && strings.IndexByte(" \t\n\r\f", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+	return p.pos > start
+}
+
+func isIdentByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+		c == '-' || c == '_' || c >= 0x80
+}
+
+func (p *selectorParser) ident() (string, error) {
+	start := p.pos
+	for !p.eof() && isIdentByte(p.s[p.pos]) {
+		p.pos++
+	}
+	if p.pos == start {
+		if p.eof() {
+			return "", fmt.Errorf("unexpected end")
+		}
+		return "", fmt.Errorf("unexpected %q at %d", p.s[p.pos], p.pos)
+	}
+	return p.s[start:p.pos], nil
+}
+
+// complex parses a complex selector, i.e. compound selectors separated by
+// combinators.
+func (p *selectorParser) complex() ([]compoundSelector, error) {
+	var (
+		cs   []compoundSelector
+		comb byte
+	)
+	p.skipSpaces()
+	for {
+		c, err := p.compound()
+		if err != nil {
+			return nil, err
+		}
+		c.combinator = comb
+		cs = append(cs, c)
+
+		spaced := p.skipSpaces()
+		if p.eof() || p.s[p.pos] == ',' {
+			return cs, nil
+		}
+		switch ch := p.s[p.pos]; ch {
+		case '>', '+', '~':
+			comb = ch
+			p.pos++
+			p.skipSpaces()
+		default:
+			if !spaced {
+				return nil, fmt.Errorf("unexpected %q at %d", ch, p.pos)
+			}
+			comb = ' '
+		}
+	}
+}
+
+// compound parses a compound selector.
+func (p *selectorParser) compound() (c compoundSelector, err error) {
+	start := p.pos
+	if !p.eof() && p.s[p.pos] == '*' {
+		p.pos++
+	} else if !p.eof() && isIdentByte(p.s[p.pos]) {
+		c.tag, _ = p.ident()
+	}
+
+	for !p.eof() {
+		switch p.s[p.pos] {
+		case '#':
+			p.pos++
+			id, err := p.ident()
+			if err != nil {
+				return c, err
+			}
+			c.attrs = append(c.attrs, attrSelector{name: "id", op: "=", value: id})
+
+		case '.':
+			p.pos++
+			cls, err := p.ident()
+			if err != nil {
+				return c, err
+			}
+			c.attrs = append(c.attrs, attrSelector{name: "class", op: "~=", value: cls})
+
+		case '[':
+			p.pos++
+			a, err := p.attr()
+			if err != nil {
+				return c, err
+			}
+			c.attrs = append(c.attrs, a)
+
+		case ':':
+			p.pos++
+			pseudo, err := p.ident()
+			if err != nil {
+				return c, err
+			}
+			switch pseudo {
+			case "first-child", "last-child", "only-child", "empty":
+			default:
+				return c, fmt.Errorf("unsupported pseudo-class :%s", pseudo)
+			}
+			c.pseudos = append(c.pseudos, pseudo)
+
+		default:
+			if p.pos == start {
+				return c, fmt.Errorf("unexpected %q at %d", p.s[p.pos], p.pos)
+			}
+			return c, nil
+		}
+	}
+	if p.pos == start {
+		return c, fmt.Errorf("unexpected end")
+	}
+	return c, nil
+}
+
+// attr parses an attribute selector after the '['.
+func (p *selectorParser) attr() (a attrSelector, err error) {
+	p.skipSpaces()
+	if a.name, err = p.ident(); err != nil {
+		return a, err
+	}
+	a.name = strings.ToLower(a.name)
+	p.skipSpaces()
+	if p.eof() {
+		return a, fmt.Errorf("unexpected end")
+	}
+
+	if p.s[p.pos] != ']' {
+		if strings.IndexByte("~|^$*", p.s[p.pos]) >= 0 {
+			a.op = p.s[p.pos : p.pos+1]
+			p.pos++
+		}
+		if p.eof() || p.s[p.pos] != '=' {
+			return a, fmt.Errorf("expected '=' at %d", p.pos)
+		}
+		p.pos++
+		a.op += "="
+		p.skipSpaces()
+
+		if !p.eof() && (p.s[p.pos] == '"' || p.s[p.pos] == '\'') {
+			q := p.s[p.pos]
+			end := strings.IndexByte(p.s[p.pos+1:], q)
+			if end < 0 {
+				return a, fmt.Errorf("unterminated string at %d", p.pos)
+			}
+			a.value = p.s[p.pos+1 : p.pos+1+end]
+			p.pos += end + 2
+		} else if a.value, err = p.ident(); err != nil {
+			return a, err
+		}
+		p.skipSpaces()
+	}
+
+	if p.eof() || p.s[p.pos] != ']' {
+		return a, fmt.Errorf("expected ']' at %d", p.pos)
+	}
+	p.pos++
+	return a, nil
+}
+
+// Select returns the nodes in the tree rooted at root, including root itself,
+// that match the CSS selector, in document order. It panics if the selector is
+// invalid. See Selector for the supported syntax.
+func Select(root Node, selector string) []Node {
+	return MustParseSelector(selector).Select(root)
+}
@@ -0,0 +1,73 @@
+package html
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golangplus/testing/assert"
+)
+
+func selectedHTML(root Node, selector string) []string {
+	var res []string
+	for _, nd := range Select(root, selector) {
+		res = append(res, string(NodeToHTMLNode(nd, RenderOptions{DisableOmit: true})))
+	}
+	return res
+}
+
+func TestSelect(t *testing.T) {
+	root := DIV(
+		NAV(UL(
+			LI(A("/", T("Home"))).AddClass("active"),
+			LI(A("/about", T("About"))),
+			LI(A("https://example.com/", T("Ext"))).Attr("lang", "en-US"),
+		)),
+		P(T("a")).Attr("id", "first"),
+		P(T("b")),
+		SPAN(),
+	)
+
+	for _, c := range []struct {
+		sel string
+		exp []string
+	}{
+		{"nav li.active a", []string{`<a href="/">Home</a>`}},
+		{"ul > a", nil},
+		{"li:first-child > a, li:last-child a", []string{`<a href="/">Home</a>`, `<a href="https://example.com/">Ext</a>`}},
+		{"a[href^='/']", []string{`<a href="/">Home</a>`, `<a href="/about">About</a>`}},
+		{`a[href$="/about"]`, []string{`<a href="/about">About</a>`}},
+		{"li[lang|=en] a", []string{`<a href="https://example.com/">Ext</a>`}},
+		{"#first + p", []string{`<p>b</p>`}},
+		{"nav ~ p", []string{`<p id="first">a</p>`, `<p>b</p>`}},
+		{"span:empty", []string{`<span></span>`}},
+		{"DIV > *:last-child", []string{`<span></span>`}},
+	} {
+		assert.StringEqual(t, c.sel, selectedHTML(root, c.sel), c.exp)
+	}
+}
+
+func TestSelect_html(t *testing.T) {
+	h := HTML("en")
+	h.Title("Page")
+	h.Body().Child(TableOf([]int{1, 2}, Column[int]{Title: "N"}))
+
+	assert.StringEqual(t, "title", selectedHTML(h, "head > title"), []string{`<title>Page</title>`})
+	assert.Equal(t, "td", len(Select(h, "table td")), 2)
+	assert.True(t, "html", MustParseSelector("html[lang=en]").Matches(h))
+}
+
+func TestParseSelector_errors(t *testing.T) {
+	for _, sel := range []string{"", "a >", "a[href", "a[href=]", "li:hover", "a..b", "a, "} {
+		_, err := ParseSelector(sel)
+		assert.True(t, sel, err != nil)
+	}
+}
+
+func ExampleSelect() {
+	menu := UL(LI(T("Home")).AddClass("active"), LI(T("About")))
+	for _, nd := range Select(menu, "li.active") {
+		fmt.Println(NodeToHTMLNode(nd, DefaultOptions))
+	}
+	// OUTPUT:
+	// <li class="active">Home</li>
+}
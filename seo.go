@@ -0,0 +1,205 @@
+package html
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	. "github.com/gohtml/elements"
+)
+
+// OG contains the Open Graph metadata of a page.
+// http://ogp.me/
+type OG struct {
+	// Required fields.
+	Title string
+	// e.g. "website" or "article"
+	Type string
+	// The canonical URL of the page. Must be absolute.
+	URL URL
+	// Must be absolute.
+	Image URL
+
+	// Optional fields.
+	ImageAlt    string
+	Description string
+	SiteName    string
+	// e.g. "en_US". If empty, the lang of the Html is used.
+	Locale string
+}
+
+// TwitterCard contains the metadata of a Twitter card.
+// https://dev.twitter.com/cards/markup
+type TwitterCard struct {
+	// Required. One of "summary", "summary_large_image", "app" and "player".
+	Card string
+
+	// Optional fields.
+	// The @username of the website.
+	Site string
+	// The @username of the content creator.
+	Creator     string
+	Title       string
+	Description string
+	// Must be absolute if not empty.
+	Image    URL
+	ImageAlt string
+}
+
+var (
+	hreflangRegexp  = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{1,8})*$`)
+	twitterCardType = map[string]bool{
+		"summary":             true,
+		"summary_large_image": true,
+		"app":                 true,
+		"player":              true,
+	}
+)
+
+// checkAbsURL returns a non-nil error if u is not an absolute http(s) URL.
+func checkAbsURL(field string, u URL) error {
+	if u == "" {
+		return fmt.Errorf("%s is required", field)
+	}
+	pu, err := url.Parse(string(u))
+	if err != nil {
+		return fmt.Errorf("%s: %v", field, err)
+	}
+	if pu.Scheme != "http" && pu.Scheme != "https" || pu.Host == "" {
+		return fmt.Errorf("%s: %q is not an absolute http(s) URL", field, u)
+	}
+	return nil
+}
+
+// Description sets the description META of the document.
+func (h *Html) Description(desc string) *Html {
+	h.head.Meta("description", desc)
+	return h
+}
+
+// Robots sets the robots META of the document, e.g. Robots("noindex", "nofollow").
+func (h *Html) Robots(directives ...string) *Html {
+	h.head.Meta("robots", strings.Join(directives, ", "))
+	return h
+}
+
+// Canonical sets the canonical URL of the document. href must be absolute,
+// otherwise the error is reported when the document is rendered, until a valid
+// URL is set.
+func (h *Html) Canonical(href URL) *Html {
+	if err := checkAbsURL("canonical", href); err != nil {
+		h.head.Put("canonical", failedNode{err})
+		return h
+	}
+	h.head.Canonical(href)
+	return h
+}
+
+// AlternateLang adds a link to a translation of the document. hreflang is a
+// language tag, e.g. "en-US", or "x-default". href must be absolute. Invalid
+// values are reported when the document is rendered, until valid ones are set
+// for the same hreflang.
+func (h *Html) AlternateLang(hreflang string, href URL) *Html {
+	key := "alternate:" + hreflang
+	if hreflang != "x-default" && !hreflangRegexp.MatchString(hreflang) {
+		h.head.Put(key, failedNode{fmt.Errorf("hreflang: %q is not a valid language tag", hreflang)})
+		return h
+	}
+	if err := checkAbsURL("href", href); err != nil {
+		h.head.Put(key, failedNode{err})
+		return h
+	}
+	h.head.Put(key, LINK(href, "alternate").Attr("hreflang", hreflang))
+	return h
+}
+
+// OpenGraph sets the Open Graph METAs of the document. Nothing is set if any
+// required field is missing or invalid.
+func (h *Html) OpenGraph(og OG) error {
+	if og.Title == "" {
+		return fmt.Errorf("og:title is required")
+	}
+	if og.Type == "" {
+		return fmt.Errorf("og:type is required")
+	}
+	if err := checkAbsURL("og:url", og.URL); err != nil {
+		return err
+	}
+	if err := checkAbsURL("og:image", og.Image); err != nil {
+		return err
+	}
+
+	h.head.MetaProperty("og:title", og.Title)
+	h.head.MetaProperty("og:type", og.Type)
+	h.head.MetaProperty("og:url", string(og.URL))
+	h.head.MetaProperty("og:image", string(og.Image))
+	h.metaPropertyIfNotEmpty("og:image:alt", og.ImageAlt)
+	h.metaPropertyIfNotEmpty("og:description", og.Description)
+	h.metaPropertyIfNotEmpty("og:site_name", og.SiteName)
+	if og.Locale != "" {
+		h.head.MetaProperty("og:locale", og.Locale)
+	} else {
+		h.head.Put("meta:property:og:locale", ogLocale{h})
+	}
+	return nil
+}
+
+// ogLocale is the og:locale META derived from the lang of an Html when it is
+// rendered, so that the lang can be changed after OpenGraph is called.
+type ogLocale struct {
+	h *Html
+}
+
+// Implementation of Node interface
+func (l ogLocale) Type() TagType {
+	return METATag
+}
+
+// Implementation of Node interface
+func (l ogLocale) WriteTo(b Writer, opt RenderOptions, parent *Element, childIndex int) {
+	lang, ok := l.h.GetAttr("lang")
+	if !ok || lang == "" {
+		return
+	}
+	META().Attr("property", "og:locale").Attr("content", strings.Replace(lang, "-", "_", -1)).WriteTo(b, opt, parent, childIndex)
+}
+
+// TwitterCard sets the Twitter card METAs of the document. Nothing is set if
+// any field is invalid.
+func (h *Html) TwitterCard(tc TwitterCard) error {
+	if !twitterCardType[tc.Card] {
+		return fmt.Errorf("twitter:card: %q is not a valid card type", tc.Card)
+	}
+	for _, name := range []string{tc.Site, tc.Creator} {
+		if name != "" && !strings.HasPrefix(name, "@") {
+			return fmt.Errorf("twitter: %q should start with @", name)
+		}
+	}
+	if tc.Image != "" {
+		if err := checkAbsURL("twitter:image", tc.Image); err != nil {
+			return err
+		}
+	}
+
+	h.head.Meta("twitter:card", tc.Card)
+	h.metaIfNotEmpty("twitter:site", tc.Site)
+	h.metaIfNotEmpty("twitter:creator", tc.Creator)
+	h.metaIfNotEmpty("twitter:title", tc.Title)
+	h.metaIfNotEmpty("twitter:description", tc.Description)
+	h.metaIfNotEmpty("twitter:image", string(tc.Image))
+	h.metaIfNotEmpty("twitter:image:alt", tc.ImageAlt)
+	return nil
+}
+
+func (h *Html) metaIfNotEmpty(name, content string) {
+	if content != "" {
+		h.head.Meta(name, content)
+	}
+}
+
+func (h *Html) metaPropertyIfNotEmpty(property, content string) {
+	if content != "" {
+		h.head.MetaProperty(property, content)
+	}
+}
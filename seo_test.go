@@ -0,0 +1,91 @@
+package html
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/golangplus/testing/assert"
+)
+
+func TestHtml_Canonical(t *testing.T) {
+	h := HTML("")
+	var b bytes.Buffer
+	assert.Error(t, Render(&b, HTML("").Canonical("/page"), DefaultOptions))
+	assert.Equal(t, "b.Len()", b.Len(), 0)
+	assert.Equal(t, "ftp", fmt.Sprint(Render(&b, h.Canonical("ftp://example.com/page"), DefaultOptions)),
+		`canonical: "ftp://example.com/page" is not an absolute http(s) URL`)
+	h.Canonical("https://example.com/page").Canonical("https://example.com/other")
+
+	assert.StringEqual(t, "html", NodeToHTMLNode(h, RenderOptions{SortAttr: true}),
+		`<!DOCTYPE html>
+<meta charset="utf-8"><link href="https://example.com/other" rel="canonical">`)
+}
+
+func TestHtml_AlternateLang(t *testing.T) {
+	var b bytes.Buffer
+	assert.Equal(t, "invalid hreflang", fmt.Sprint(Render(&b, HTML("en").AlternateLang("en US", "https://example.com/"), DefaultOptions)),
+		`hreflang: "en US" is not a valid language tag`)
+	assert.Error(t, Render(&b, HTML("en").AlternateLang("de", "/de/"), DefaultOptions))
+	assert.Equal(t, "b.Len()", b.Len(), 0)
+
+	h := HTML("en").AlternateLang("de", "/de/")
+	h.AlternateLang("de", "https://example.com/de/").AlternateLang("x-default", "https://example.com/")
+	assert.NoError(t, Render(&b, h, DefaultOptions))
+
+	assert.StringEqual(t, "html", NodeToHTMLNode(h, RenderOptions{SortAttr: true}),
+		`<!DOCTYPE html>
+<html lang="en"><meta charset="utf-8"><link href="https://example.com/de/" hreflang="de" rel="alternate">`+
+			`<link href="https://example.com/" hreflang="x-default" rel="alternate">`)
+}
+
+func TestHtml_OpenGraph(t *testing.T) {
+	h := HTML("")
+	assert.True(t, "missing title", h.OpenGraph(OG{Type: "website"}) != nil)
+	assert.True(t, "relative image", h.OpenGraph(OG{
+		Title: "T", Type: "website", URL: "https://example.com/", Image: "/a.png",
+	}) != nil)
+	assert.StringEqual(t, "html", NodeToHTMLNode(h, DefaultOptions),
+		`<!DOCTYPE html>
+<meta charset="utf-8">`)
+}
+
+func TestHtml_TwitterCard(t *testing.T) {
+	h := HTML("")
+	assert.True(t, "invalid card", h.TwitterCard(TwitterCard{Card: "large"}) != nil)
+	assert.True(t, "invalid site", h.TwitterCard(TwitterCard{Card: "summary", Site: "example"}) != nil)
+	assert.NoError(t, h.TwitterCard(TwitterCard{Card: "summary", Site: "@example", Title: "T"}))
+
+	assert.StringEqual(t, "html", NodeToHTMLNode(h, RenderOptions{SortAttr: true}),
+		`<!DOCTYPE html>
+<meta charset="utf-8"><meta content="summary" name="twitter:card"><meta content="@example" name="twitter:site">`+
+			`<meta content="T" name="twitter:title">`)
+}
+
+func ExampleHtml_OpenGraph() {
+	h := HTML("en-US")
+	h.Description("All about gophers")
+	h.Robots("noindex", "follow")
+	h.OpenGraph(OG{
+		Title: "Gophers",
+		Type:  "article",
+		URL:   "https://example.com/gophers",
+		Image: "https://example.com/gopher.png",
+	})
+	fmt.Println(NodeToHTMLNode(h, RenderOptions{SortAttr: true}))
+	// OUTPUT:
+	// <!DOCTYPE html>
+	// <html lang="en-US"><meta charset="utf-8"><meta content="All about gophers" name="description"><meta content="noindex, follow" name="robots"><meta content="Gophers" property="og:title"><meta content="article" property="og:type"><meta content="https://example.com/gophers" property="og:url"><meta content="https://example.com/gopher.png" property="og:image"><meta content="en_US" property="og:locale">
+}
+
+func TestHtml_OpenGraph_locale(t *testing.T) {
+	h := HTML("")
+	assert.NoError(t, h.OpenGraph(OG{
+		Title: "T", Type: "website", URL: "https://example.com/", Image: "https://example.com/a.png",
+	}))
+	h.Lang("pt-BR&x")
+	assert.StringEqual(t, "html", NodeToHTMLNode(h.HeadSet(), RenderOptions{SortAttr: true}),
+		`<meta charset="utf-8"><meta content="T" property="og:title"><meta content="website" property="og:type">`+
+			`<meta content="https://example.com/" property="og:url"><meta content="https://example.com/a.png" property="og:image">`+
+			`<meta content="pt_BR&amp;x" property="og:locale">`)
+}
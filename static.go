@@ -0,0 +1,157 @@
+package html
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/golangplus/bytes"
+
+	. "github.com/gohtml/elements"
+)
+
+// StaticNode is a pre-rendered Node created by Static.
+type StaticNode struct {
+	tagType TagType
+	html    bytesp.ByteSlice
+	// The first error reported when the source was rendered.
+	err error
+	src Node
+}
+
+var _ Node = (*StaticNode)(nil)
+
+// Static renders n with opt once and returns a Node writing the result, which
+// is useful for parts of pages that never change, e.g. the navigation. The tags
+// of n itself are never omitted since its siblings are unknown, but the
+// returned Node reports the type of n so that omission of the tags of its
+// siblings and parent is not changed.
+//
+// Options given when rendering the returned Node are ignored, so per-request
+// content, e.g. FORMs needing CSRF tokens, should not be made static. Errors
+// reported when rendering n, e.g. an unknown asset, are reported again every
+// time the returned Node is rendered. n should not be modified after calling
+// Static.
+func Static(n Node, opt RenderOptions) Node {
+	switch n.(type) {
+	case HTMLNode, *StaticNode:
+		return n
+	}
+
+	var errs []error
+	opt.errs = &errs
+	s := &StaticNode{
+		tagType: n.Type(),
+		src:     n,
+	}
+	if e, ok := n.(*Element); ok {
+		fragmentRoot{e}.WriteTo(&s.html, opt, nil, 0)
+	} else {
+		n.WriteTo(&s.html, opt, nil, 0)
+	}
+	if len(errs) > 0 {
+		s.err = errs[0]
+	}
+	return s
+}
+
+// Implementation of Node interface
+func (s *StaticNode) Type() TagType {
+	return s.tagType
+}
+
+// Implementation of Node interface
+func (s *StaticNode) WriteTo(b Writer, opt RenderOptions, parent *Element, childIndex int) {
+	if s.err != nil {
+		opt.fail(s.err)
+	}
+	b.Write(s.html)
+}
+
+// Children returns the source node so that Walk visits it, e.g. for
+// HeadContributors in it.
+func (s *StaticNode) Children() []Node {
+	return []Node{s.src}
+}
+
+// Cache memoizes StaticNodes of components keyed by the names of the
+// components and their props. See Memo.
+//
+// A Cache is safe for concurrent use.
+type Cache struct {
+	opt RenderOptions
+	max int
+
+	mu sync.Mutex
+	// The most recently used entry is at the front.
+	lru     *list.List
+	entries map[cacheKey]*list.Element
+}
+
+type cacheKey struct {
+	component string
+	props     interface{}
+}
+
+type cacheEntry struct {
+	key  cacheKey
+	node Node
+}
+
+// NewCache returns a Cache rendering nodes with opt and keeping at most max
+// nodes, evicting the least recently used ones. If max is not positive, the
+// number is unlimited.
+func NewCache(opt RenderOptions, max int) *Cache {
+	return &Cache{
+		opt:     opt,
+		max:     max,
+		lru:     list.New(),
+		entries: make(map[cacheKey]*list.Element),
+	}
+}
+
+// Memo returns the Static of the node created by render with props. The node is
+// cached in c by the component name and props, so render is called only once
+// for the same props until the entry is evicted.
+func Memo[P comparable](c *Cache, component string, props P, render func(props P) Node) Node {
+	key := cacheKey{component: component, props: props}
+	c.mu.Lock()
+	if el, ok := c.entries[key]; ok {
+		c.lru.MoveToFront(el)
+		c.mu.Unlock()
+		return el.Value.(*cacheEntry).node
+	}
+	c.mu.Unlock()
+
+	// Render without the lock. Concurrent misses may render the same node more
+	// than once, which is harmless.
+	nd := Static(render(props), c.opt)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		c.lru.MoveToFront(el)
+		return el.Value.(*cacheEntry).node
+	}
+	c.entries[key] = c.lru.PushFront(&cacheEntry{key: key, node: nd})
+	if c.max > 0 && c.lru.Len() > c.max {
+		last := c.lru.Back()
+		c.lru.Remove(last)
+		delete(c.entries, last.Value.(*cacheEntry).key)
+	}
+	return nd
+}
+
+// Len returns the number of cached nodes.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+// Clear removes all cached nodes, e.g. after the data they depend on changed.
+func (c *Cache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lru.Init()
+	c.entries = make(map[cacheKey]*list.Element)
+}
@@ -0,0 +1,104 @@
+package html
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/golangplus/testing/assert"
+
+	. "github.com/gohtml/elements"
+)
+
+func TestStatic(t *testing.T) {
+	nav := NAV(UL(LI(A("/", T("Home"))), LI(A("/about", T("About"))))).Attr("id", "nav")
+	st := Static(nav, DefaultOptions)
+	assert.Equal(t, "type", st.Type(), NAVTag)
+	assert.StringEqual(t, "html", NodeToHTMLNode(st, DefaultOptions), NodeToHTMLNode(nav, DefaultOptions))
+	assert.Equal(t, "static of static", Static(st, DefaultOptions), st)
+	assert.Equal(t, "text", Static(T("a<b"), DefaultOptions), Node(HTMLNode("a&lt;b")))
+}
+
+func TestStatic_omission(t *testing.T) {
+	// The type of a static LI lets the previous LI omit its end tag, and the
+	// static LI keeps its own.
+	ul := UL(LI(T("a")), Static(LI(T("b")), DefaultOptions))
+	assert.StringEqual(t, "ul", NodeToHTMLNode(ul, DefaultOptions), `<ul><li>a<li>b</li></ul>`)
+
+	// A static P before a DIV cannot omit its end tag, but a P before a static DIV
+	// can.
+	div := DIV(P(T("a")), Static(DIV(), DefaultOptions), Static(P(T("b")), DefaultOptions), DIV())
+	assert.StringEqual(t, "div", NodeToHTMLNode(div, DefaultOptions), `<div><p>a<div></div><p>b</p><div></div></div>`)
+
+	tbody := Static(TBODY(TR(TD(T("1")))), DefaultOptions)
+	assert.StringEqual(t, "tbody", NodeToHTMLNode(TABLE().Child(tbody), DefaultOptions), `<table><tbody><tr><td>1</tbody></table>`)
+}
+
+func TestStatic_walk(t *testing.T) {
+	h := HTML("")
+	h.Body().Child(Static(DIV(&widget{Element: Element{Void: Void{tagType: SPANTag}}}), DefaultOptions))
+	assert.StringEqual(t, "html", NodeToHTMLNode(h, RenderOptions{SortAttr: true}), `<!DOCTYPE html>
+<meta charset="utf-8"><title>Widget</title><link href="widget.css" rel="stylesheet" type="text/css"><div><span></span></div>`)
+	assert.Equal(t, "span", len(Select(h, "body > div > span")), 1)
+}
+
+func TestStatic_errors(t *testing.T) {
+	st := Static(SCRIPT(Asset("missing.js"), ""), RenderOptions{Assets: &AssetManifest{}})
+	var b bytes.Buffer
+	assert.True(t, "err", Render(&b, DIV(st), DefaultOptions) != nil)
+	assert.Equal(t, "written", b.Len(), 0)
+}
+
+type cardProps struct {
+	Title string
+	N     int
+}
+
+func TestMemo(t *testing.T) {
+	c := NewCache(DefaultOptions, 2)
+	calls := 0
+	card := func(p cardProps) Node {
+		calls++
+		return DIV(H2(T(p.Title)), Tf("%d", p.N)).AddClass("card")
+	}
+
+	a := Memo(c, "card", cardProps{"A", 1}, card)
+	assert.Equal(t, "same", Memo(c, "card", cardProps{"A", 1}, card), a)
+	assert.Equal(t, "calls", calls, 1)
+	assert.StringEqual(t, "html", NodeToHTMLNode(a, DefaultOptions), `<div class="card"><h2>A</h2>1</div>`)
+
+	Memo(c, "card", cardProps{"B", 1}, card)
+	Memo(c, "other", cardProps{"A", 1}, card)
+	assert.Equal(t, "calls", calls, 3)
+	assert.Equal(t, "len", c.Len(), 2)
+	// cardProps{"A", 1} of "card" has been evicted.
+	Memo(c, "card", cardProps{"A", 1}, card)
+	assert.Equal(t, "calls", calls, 4)
+
+	c.Clear()
+	assert.Equal(t, "len", c.Len(), 0)
+}
+
+func TestMemo_concurrent(t *testing.T) {
+	c := NewCache(DefaultOptions, 0)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				Memo(c, "n", j%10, func(n int) Node { return SPAN(Tf("%d", n)) })
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, "len", c.Len(), 10)
+}
+
+func ExampleStatic() {
+	footer := Static(FOOTER(P(T("© Gophers")), NAV(A("/terms", T("Terms")))), DefaultOptions)
+	fmt.Println(NodeToHTMLNode(DIV(P(T("Content")), footer), DefaultOptions))
+	// OUTPUT:
+	// <div><p>Content<footer><p>© Gophers<nav><a href="/terms">Terms</a></nav></footer></div>
+}
@@ -0,0 +1,252 @@
+package html
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gohtml/utils"
+)
+
+type styleProp struct {
+	name  string
+	value string
+}
+
+// Style is a list of CSS declarations for the style attribute of an element.
+// Setting a property again replaces its value but keeps its position, so the
+// declarations are always serialized in the order they were first set.
+//
+// Property names are validated and values are escaped, so a value can never
+// break out of its declaration. Invalid declarations are ignored.
+type Style struct {
+	props []styleProp
+}
+
+// NewStyle returns an empty Style.
+func NewStyle() *Style {
+	return &Style{}
+}
+
+// Px returns a length in pixels, e.g. "10px".
+func Px(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64) + "px"
+}
+
+// Em returns a length in ems, e.g. "1.5em".
+func Em(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64) + "em"
+}
+
+// Rem returns a length in rems, e.g. "2rem".
+func Rem(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64) + "rem"
+}
+
+// Percent returns a percentage, e.g. "50%".
+func Percent(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64) + "%"
+}
+
+func validPropName(name string) bool {
+	custom := strings.HasPrefix(name, "--")
+	if custom {
+		name = name[2:]
+	}
+	if name == "" {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case c >= 'a' && c <= 'z', c == '-':
+		case custom && (c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_'):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// escapeCSSChar appends the CSS escape of c to b.
+func escapeCSSChar(b []byte, c byte) []byte {
+	b = append(b, '\\')
+	b = strconv.AppendInt(b, int64(c), 16)
+	return append(b, ' ')
+}
+
+func isHexDigit(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
+
+// escapeCSSValue escapes characters which could end the declaration or the
+// rule, or start a comment, with CSS escapes. Valid strings and escapes are
+// kept as they are, and an unterminated string is closed. '<' and '>' are
+// always escaped so that the value can never close a STYLE element.
+func escapeCSSValue(value string) string {
+	b := make([]byte, 0, len(value)+8)
+	var quote byte
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c < ' ' || c == 0x7f || c == '<' || c == '>':
+			b = escapeCSSChar(b, c)
+
+		case c == '\\':
+			if i+1 == len(value) || value[i+1] < ' ' {
+				b = escapeCSSChar(b, c)
+				break
+			}
+			// Keep the escape, including the whitespace ending a hex escape.
+			j := i + 1
+			if isHexDigit(value[j]) {
+				for j < len(value) && j < i+7 && isHexDigit(value[j]) {
+					j++
+				}
+				if j < len(value) && value[j] == ' ' {
+					j++
+				}
+			} else if value[j] != '<' && value[j] != '>' {
+				j++
+			}
+			b = append(b, value[i:j]...)
+			i = j - 1
+
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+			b = append(b, c)
+
+		case c == '"' || c == '\'':
+			quote = c
+			b = append(b, c)
+
+		case c == ';' || c == '{' || c == '}' || c == '/' && i+1 < len(value) && value[i+1] == '*':
+			b = escapeCSSChar(b, c)
+
+		default:
+			b = append(b, c)
+		}
+	}
+	if quote != 0 {
+		b = append(b, quote)
+	}
+	return string(b)
+}
+
+// Set sets a property. name is a lowercase property name or a custom property
+// starting with "--". The declaration is ignored if name is invalid, or value
+// is empty or contains "expression(" or "javascript:".
+func (s *Style) Set(name, value string) *Style {
+	value = strings.TrimSpace(value)
+	if !validPropName(name) || value == "" {
+		return s
+	}
+	if lv := strings.ToLower(value); strings.Contains(lv, "expression(") || strings.Contains(lv, "javascript:") {
+		return s
+	}
+	s.put(styleProp{name, escapeCSSValue(value)})
+	return s
+}
+
+// Var sets a custom property. The "--" prefix of name is optional.
+func (s *Style) Var(name, value string) *Style {
+	if !strings.HasPrefix(name, "--") {
+		name = "--" + name
+	}
+	return s.Set(name, value)
+}
+
+// Merge sets all the properties of o.
+func (s *Style) Merge(o *Style) *Style {
+	for _, p := range o.props {
+		s.put(p)
+	}
+	return s
+}
+
+// put sets a validated and escaped declaration.
+func (s *Style) put(p styleProp) {
+	for i := range s.props {
+		if s.props[i].name == p.name {
+			s.props[i].value = p.value
+			return
+		}
+	}
+	s.props = append(s.props, p)
+}
+
+// String returns the serialized declarations, e.g. "display:flex;margin:0 auto".
+func (s *Style) String() string {
+	var b strings.Builder
+	for i, p := range s.props {
+		if i > 0 {
+			b.WriteByte(';')
+		}
+		b.WriteString(p.name)
+		b.WriteByte(':')
+		b.WriteString(p.value)
+	}
+	return b.String()
+}
+
+// Display sets the display property.
+func (s *Style) Display(v string) *Style {
+	return s.Set("display", v)
+}
+
+// Margin sets the margin property. values are joined by spaces, e.g.
+// Margin("0", "auto").
+func (s *Style) Margin(values ...string) *Style {
+	return s.Set("margin", strings.Join(values, " "))
+}
+
+// Padding sets the padding property. values are joined by spaces.
+func (s *Style) Padding(values ...string) *Style {
+	return s.Set("padding", strings.Join(values, " "))
+}
+
+// Width sets the width property.
+func (s *Style) Width(v string) *Style {
+	return s.Set("width", v)
+}
+
+// Height sets the height property.
+func (s *Style) Height(v string) *Style {
+	return s.Set("height", v)
+}
+
+// Color sets the color property.
+func (s *Style) Color(v string) *Style {
+	return s.Set("color", v)
+}
+
+// Background sets the background property.
+func (s *Style) Background(v string) *Style {
+	return s.Set("background", v)
+}
+
+// FontSize sets the font-size property.
+func (s *Style) FontSize(v string) *Style {
+	return s.Set("font-size", v)
+}
+
+// FontWeight sets the font-weight property.
+func (s *Style) FontWeight(v string) *Style {
+	return s.Set("font-weight", v)
+}
+
+// Style merges the declarations of st into the style attribute of the node.
+// Declarations set by previous calls are kept unless set again in st. A style
+// attribute set by Attr is replaced.
+func (v *Void) Style(st *Style) *Void {
+	v.style.Merge(st)
+	v.attributes.Put("style", HTMLNode(utils.EscapeAttr(v.style.String())))
+	return v
+}
+
+// Style is same as Void.Style but returns a *Element.
+func (e *Element) Style(st *Style) *Element {
+	e.Void.Style(st)
+	return e
+}
@@ -0,0 +1,65 @@
+package html
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golangplus/testing/assert"
+)
+
+func TestStyle_Set(t *testing.T) {
+	s := NewStyle().
+		Set("Color", "red").
+		Set("bad name", "red").
+		Set("width", "").
+		Set("background", "url(javascript:alert(1))").
+		Set("font-family", `a;}</style><script>"x"/*`).
+		Var("main-color", "#fff").
+		Set("--Bad!", "x")
+	assert.Equal(t, "s", s.String(), `font-family:a\3b \7d \3c /style\3e \3c script\3e "x"\2f *;--main-color:#fff`)
+}
+
+func TestStyle_Set_strings(t *testing.T) {
+	for _, c := range []struct {
+		value, escaped string
+	}{
+		{`"Open Sans", sans-serif`, `"Open Sans", sans-serif`},
+		{`'a;b{c}' x;y`, `'a;b{c}' x\3b y`},
+		{`"unterminated; color:red`, `"unterminated; color:red"`},
+		{`"it\"s", \31 23`, `"it\"s", \31 23`},
+		{`"</style>"`, `"\3c /style\3e "`},
+		{`a\`, `a\5c `},
+		{"\"a\nb\"", `"a\a b"`},
+		{`url("x;y.png")`, `url("x;y.png")`},
+	} {
+		assert.Equal(t, c.value, NewStyle().Set("font-family", c.value).String(), "font-family:"+c.escaped)
+	}
+}
+
+func TestVoid_Style(t *testing.T) {
+	div := DIV().Style(NewStyle().Display("flex").Color("red"))
+	div.Style(NewStyle().Color("blue").Margin("0", "auto"))
+	assert.StringEqual(t, "div", NodeToHTMLNode(div, DefaultOptions),
+		`<div style="display:flex;color:blue;margin:0 auto"></div>`)
+
+	div.Style(NewStyle().Set("font-family", `"Open Sans", sans-serif`))
+	div.Style(NewStyle().Set("content", `"\201C"`))
+	assert.StringEqual(t, "div", NodeToHTMLNode(div, DefaultOptions),
+		`<div style="display:flex;color:blue;margin:0 auto;font-family:&quot;Open Sans&quot;, sans-serif;content:&quot;\201C&quot;"></div>`)
+
+	div.Attr("style", "color:green")
+	div.Style(NewStyle().Width(Px(10)))
+	assert.StringEqual(t, "div", NodeToHTMLNode(div, DefaultOptions),
+		`<div style="width:10px"></div>`)
+}
+
+func ExampleStyle() {
+	p := P(T("Hello")).Attr("title", "Greeting").Style(NewStyle().
+		FontSize(Rem(1.5)).
+		Padding(Px(4), Percent(5)).
+		Var("accent", "rebeccapurple").
+		Color("var(--accent)"))
+	fmt.Println(NodeToHTMLNode(DIV(p), RenderOptions{SortAttr: true}))
+	// OUTPUT:
+	// <div><p style="font-size:1.5rem;padding:4px 5%;--accent:rebeccapurple;color:var(--accent)" title="Greeting">Hello</div>
+}
@@ -0,0 +1,95 @@
+// Package svg contains constructors of SVG elements embedded in HTML.
+//
+// The elements are html.Foreign, so names of elements and attributes keep
+// their cases, e.g. "viewBox" and "linearGradient".
+package svg
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gohtml/html"
+)
+
+// The namespace of SVG. It is only necessary for standalone SVG documents.
+const Namespace = "http://www.w3.org/2000/svg"
+
+func num(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// Point is a point in a coordinate system.
+type Point struct {
+	X, Y float64
+}
+
+// SVG creates an svg element. viewBox is ignored if empty.
+func SVG(viewBox string, children ...html.Node) *html.Foreign {
+	return html.FOREIGN("svg", children...).NonEmptyAttr("viewBox", viewBox)
+}
+
+// G creates a g element.
+func G(children ...html.Node) *html.Foreign {
+	return html.FOREIGN("g", children...)
+}
+
+// DEFS creates a defs element.
+func DEFS(children ...html.Node) *html.Foreign {
+	return html.FOREIGN("defs", children...)
+}
+
+// SYMBOL creates a symbol element, which can be referenced by USE with "#"+id.
+func SYMBOL(id, viewBox string, children ...html.Node) *html.Foreign {
+	return html.FOREIGN("symbol", children...).ID(id).NonEmptyAttr("viewBox", viewBox)
+}
+
+// USE creates a use element.
+func USE(href string) *html.Foreign {
+	return html.FOREIGN("use").Attr("href", href)
+}
+
+// PATH creates a path element.
+func PATH(d string) *html.Foreign {
+	return html.FOREIGN("path").Attr("d", d)
+}
+
+// CIRCLE creates a circle element.
+func CIRCLE(cx, cy, r float64) *html.Foreign {
+	return html.FOREIGN("circle").Attr("cx", num(cx)).Attr("cy", num(cy)).Attr("r", num(r))
+}
+
+// RECT creates a rect element.
+func RECT(x, y, width, height float64) *html.Foreign {
+	return html.FOREIGN("rect").Attr("x", num(x)).Attr("y", num(y)).
+		Attr("width", num(width)).Attr("height", num(height))
+}
+
+// LINE creates a line element.
+func LINE(x1, y1, x2, y2 float64) *html.Foreign {
+	return html.FOREIGN("line").Attr("x1", num(x1)).Attr("y1", num(y1)).
+		Attr("x2", num(x2)).Attr("y2", num(y2))
+}
+
+// POLYLINE creates a polyline element.
+func POLYLINE(points ...Point) *html.Foreign {
+	ps := make([]string, len(points))
+	for i, p := range points {
+		ps[i] = num(p.X) + "," + num(p.Y)
+	}
+	return html.FOREIGN("polyline").Attr("points", strings.Join(ps, " "))
+}
+
+// TEXT creates a text element containing the escaped text.
+func TEXT(x, y float64, text string) *html.Foreign {
+	return html.FOREIGN("text").Attr("x", num(x)).Attr("y", num(y)).T(text)
+}
+
+// LINEARGRADIENT creates a linearGradient element. Reference it by "url(#id)".
+func LINEARGRADIENT(id string, stops ...html.Node) *html.Foreign {
+	return html.FOREIGN("linearGradient", stops...).ID(id)
+}
+
+// STOP creates a stop element of a gradient. offset is e.g. "50%".
+func STOP(offset, color string) *html.Foreign {
+	return html.FOREIGN("stop").Attr("offset", offset).Attr("stop-color", color)
+}
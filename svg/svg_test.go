@@ -0,0 +1,20 @@
+package svg
+
+import (
+	"fmt"
+
+	"github.com/gohtml/html"
+)
+
+func ExampleSVG() {
+	icon := SVG("0 0 24 24",
+		DEFS(LINEARGRADIENT("g", STOP("0%", "#fff"), STOP("100%", "#000"))),
+		RECT(0, 0, 24, 24).Attr("fill", "url(#g)"),
+		CIRCLE(12, 12, 5.5),
+		POLYLINE(Point{0, 0}, Point{12, 24}),
+		TEXT(2, 20, "a < b"),
+	)
+	fmt.Println(html.NodeToHTMLNode(html.DIV(icon), html.RenderOptions{SortAttr: true}))
+	// OUTPUT:
+	// <div><svg viewBox="0 0 24 24"><defs><linearGradient id="g"><stop offset="0%" stop-color="#fff"/><stop offset="100%" stop-color="#000"/></linearGradient></defs><rect fill="url(#g)" height="24" width="24" x="0" y="0"/><circle cx="12" cy="12" r="5.5"/><polyline points="0,0 12,24"/><text x="2" y="20">a &lt; b</text></svg></div>
+}
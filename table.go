@@ -0,0 +1,204 @@
+package html
+
+import (
+	"fmt"
+	"strconv"
+
+	. "github.com/gohtml/elements"
+)
+
+// SortDir is the sort direction of a column of a Table.
+type SortDir int
+
+const (
+	Unsorted SortDir = iota
+	Ascending
+	Descending
+)
+
+// Column defines a column of a Table.
+type Column[R any] struct {
+	Title string
+	// Value returns the value of the cell of a row, which is formatted by Format.
+	Value func(row R) interface{}
+	// Format formats the value as the text of the cell. Default is fmt.Sprint.
+	Format func(v interface{}) string
+	// Cell returns the content of the cell of a row. If not nil, Value and Format
+	// are ignored.
+	Cell func(row R) Node
+	// Span returns the colspan and rowspan of the cell of a row. Values less than
+	// 2 are ignored. Cells covered by a spanning cell are skipped. A colspan is
+	// cut at a cell covered by a rowspan, and a rowspan at the last row.
+	Span func(row R) (colspan, rowspan int)
+	// Footer returns the content of the cell in the footer, e.g. a total. No
+	// footer is generated if no column has a Footer.
+	Footer func(rows []R) Node
+
+	// The key of the column used by Table.SortedBy and the sort URL. Columns
+	// with empty keys are not sortable.
+	SortKey string
+}
+
+func (c *Column[R]) cell(row R) Node {
+	if c.Cell != nil {
+		return c.Cell(row)
+	}
+	if c.Value == nil {
+		return T("")
+	}
+	v := c.Value(row)
+	if c.Format != nil {
+		return T(c.Format(v))
+	}
+	return T(fmt.Sprint(v))
+}
+
+// Table renders a slice of rows as a TABLE element. Call TableOf to create one.
+type Table[R any] struct {
+	rows    []R
+	cols    []Column[R]
+	caption []Node
+	sortKey string
+	sortDir SortDir
+	sortURL func(key string, dir SortDir) string
+}
+
+// TableOf creates a Table of the rows with the columns.
+func TableOf[R any](rows []R, cols ...Column[R]) *Table[R] {
+	return &Table[R]{
+		rows: rows,
+		cols: cols,
+	}
+}
+
+// Caption sets the contents of the CAPTION.
+func (t *Table[R]) Caption(children ...Node) *Table[R] {
+	t.caption = children
+	return t
+}
+
+// SortedBy marks the column of the key as sorted in dir, using aria-sort in
+// its header.
+func (t *Table[R]) SortedBy(key string, dir SortDir) *Table[R] {
+	t.sortKey, t.sortDir = key, dir
+	return t
+}
+
+// SortURL sets the function returning the URL to sort by a column. If set,
+// titles of sortable columns are links to sort by them, in the reverse
+// direction if it is the sorted column.
+func (t *Table[R]) SortURL(f func(key string, dir SortDir) string) *Table[R] {
+	t.sortURL = f
+	return t
+}
+
+func (t *Table[R]) header(c *Column[R]) *Element {
+	th := TH().Attr("scope", "col")
+	if c.SortKey == "" {
+		return th.T(c.Title)
+	}
+
+	next := Ascending
+	if c.SortKey == t.sortKey {
+		switch t.sortDir {
+		case Ascending:
+			th.Attr("aria-sort", "ascending")
+			next = Descending
+		case Descending:
+			th.Attr("aria-sort", "descending")
+		}
+	}
+	if t.sortURL == nil {
+		return th.T(c.Title)
+	}
+	return th.Child(A(t.sortURL(c.SortKey, next), T(c.Title)))
+}
+
+// Element creates the TABLE element.
+func (t *Table[R]) Element() *Element {
+	table := TABLE()
+	if len(t.caption) > 0 {
+		table.Child(CAPTION(t.caption...))
+	}
+
+	head := TR()
+	hasFooter := false
+	for i := range t.cols {
+		head.ChildEls(t.header(&t.cols[i]))
+		hasFooter = hasFooter || t.cols[i].Footer != nil
+	}
+	table.ChildEls(THEAD(head))
+
+	body := TBODY()
+	// The number of following rows each column is covered by a rowspan.
+	covered := make([]int, len(t.cols))
+	for r, row := range t.rows {
+		tr := TR()
+		for i := 0; i < len(t.cols); i++ {
+			if covered[i] > 0 {
+				covered[i]--
+				continue
+			}
+
+			c := &t.cols[i]
+			td := TD(c.cell(row))
+			colspan, rowspan := 1, 1
+			if c.Span != nil {
+				colspan, rowspan = c.Span(row)
+			}
+			// A colspan stops at the end of the row or at a column covered by a
+			// rowspan above.
+			if colspan > len(t.cols)-i {
+				colspan = len(t.cols) - i
+			}
+			for j := i + 1; j < i+colspan; j++ {
+				if covered[j] > 0 {
+					colspan = j - i
+					break
+				}
+			}
+			// A rowspan stops at the last row.
+			if rowspan > len(t.rows)-r {
+				rowspan = len(t.rows) - r
+			}
+			if colspan > 1 {
+				td.attrOfEscaped("colspan", HTMLNode(strconv.Itoa(colspan)))
+			} else {
+				colspan = 1
+			}
+			if rowspan > 1 {
+				td.attrOfEscaped("rowspan", HTMLNode(strconv.Itoa(rowspan)))
+				for j := i; j < i+colspan; j++ {
+					covered[j] = rowspan - 1
+				}
+			}
+			tr.ChildEls(td)
+			i += colspan - 1
+		}
+		body.ChildEls(tr)
+	}
+	table.ChildEls(body)
+
+	if hasFooter {
+		tr := TR()
+		for i := range t.cols {
+			if f := t.cols[i].Footer; f != nil {
+				tr.ChildEls(TD(f(t.rows)))
+			} else {
+				tr.ChildEls(TD())
+			}
+		}
+		table.ChildEls(TFOOT(tr))
+	}
+	return table
+}
+
+// Implementation of Node interface
+func (t *Table[R]) Type() TagType {
+	return TABLETag
+}
+
+// Implementation of Node interface
+func (t *Table[R]) WriteTo(b Writer, opt RenderOptions, parent *Element, childIndex int) {
+	t.Element().WriteTo(b, opt, parent, childIndex)
+}
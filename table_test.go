@@ -0,0 +1,130 @@
+package html
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/golangplus/testing/assert"
+)
+
+type fruit struct {
+	Name  string
+	Price float64
+	Qty   int
+}
+
+func TestTableOf_span(t *testing.T) {
+	rows := []fruit{{"Apple", 1, 2}, {"Pear", 2, 3}, {"Fig", 3, 4}}
+	table := TableOf(rows,
+		Column[fruit]{Title: "Name", Value: func(f fruit) interface{} { return f.Name },
+			Span: func(f fruit) (int, int) {
+				if f.Name == "Apple" {
+					return 1, 2
+				}
+				return 1, 1
+			}},
+		Column[fruit]{Title: "Price", Value: func(f fruit) interface{} { return f.Price },
+			Span: func(f fruit) (int, int) {
+				if f.Name == "Fig" {
+					return 5, 1
+				}
+				return 1, 1
+			}},
+		Column[fruit]{Title: "Qty", Value: func(f fruit) interface{} { return f.Qty }},
+	)
+
+	assert.StringEqual(t, "table", NodeToHTMLNode(table, RenderOptions{SortAttr: true}),
+		`<table><thead><tr><th scope="col">Name<th scope="col">Price<th scope="col">Qty<tbody>`+
+			`<tr><td rowspan="2">Apple<td>1<td>2`+
+			`<tr><td>2<td>3`+
+			`<tr><td>Fig<td colspan="2">3</table>`)
+}
+
+func TestTableOf_spanOverlap(t *testing.T) {
+	rows := []fruit{{"Apple", 1, 2}, {"Pear", 2, 3}}
+	table := TableOf(rows,
+		Column[fruit]{Title: "Name", Value: func(f fruit) interface{} { return f.Name },
+			Span: func(f fruit) (int, int) {
+				if f.Name == "Pear" {
+					return 3, 1
+				}
+				return 1, 1
+			}},
+		Column[fruit]{Title: "Price", Value: func(f fruit) interface{} { return f.Price },
+			Span: func(f fruit) (int, int) {
+				if f.Name == "Apple" {
+					return 1, 2
+				}
+				return 1, 1
+			}},
+		Column[fruit]{Title: "Qty", Value: func(f fruit) interface{} { return f.Qty }},
+	)
+
+	// The colspan of Pear stops at the Price covered by the rowspan of Apple.
+	assert.StringEqual(t, "table", NodeToHTMLNode(table, RenderOptions{SortAttr: true}),
+		`<table><thead><tr><th scope="col">Name<th scope="col">Price<th scope="col">Qty<tbody>`+
+			`<tr><td>Apple<td rowspan="2">1<td>2`+
+			`<tr><td>Pear<td>3</table>`)
+}
+
+func TestTableOf_spanPastLastRow(t *testing.T) {
+	rows := []fruit{{"Fig", 3, 4}, {"Apple", 1, 2}, {"Pear", 2, 3}}
+	table := TableOf(rows,
+		Column[fruit]{Title: "Name", Value: func(f fruit) interface{} { return f.Name }},
+		Column[fruit]{Title: "Price", Value: func(f fruit) interface{} { return f.Price },
+			Span: func(f fruit) (int, int) {
+				switch f.Name {
+				case "Apple":
+					return 1, 5
+				case "Fig":
+					return 1, 1
+				}
+				return 1, 3
+			}},
+	)
+
+	assert.StringEqual(t, "table", NodeToHTMLNode(table, RenderOptions{SortAttr: true}),
+		`<table><thead><tr><th scope="col">Name<th scope="col">Price<tbody>`+
+			`<tr><td>Fig<td>3`+
+			`<tr><td>Apple<td rowspan="2">1`+
+			`<tr><td>Pear</table>`)
+
+	// A rowspan in the last row is dropped.
+	table = TableOf(rows[2:], table.cols...)
+	assert.StringEqual(t, "table", NodeToHTMLNode(table, RenderOptions{SortAttr: true}),
+		`<table><thead><tr><th scope="col">Name<th scope="col">Price<tbody>`+
+			`<tr><td>Pear<td>2</table>`)
+}
+
+func TestTableOf_sort(t *testing.T) {
+	table := TableOf([]fruit{}, Column[fruit]{Title: "Name", SortKey: "name"}, Column[fruit]{Title: "Qty", SortKey: "qty"}).
+		SortedBy("name", Ascending).
+		SortURL(func(key string, dir SortDir) string {
+			return "?sort=" + key + "&dir=" + strconv.Itoa(int(dir))
+		})
+	assert.StringEqual(t, "table", NodeToHTMLNode(table, RenderOptions{SortAttr: true}),
+		`<table><thead><tr><th aria-sort="ascending" scope="col"><a href="?sort=name&amp;dir=2">Name</a>`+
+			`<th scope="col"><a href="?sort=qty&amp;dir=1">Qty</a><tbody></table>`)
+}
+
+func ExampleTableOf() {
+	rows := []fruit{{"Apple", 0.5, 4}, {"Pear", 0.75, 2}}
+	price := func(v interface{}) string { return fmt.Sprintf("$%.2f", v) }
+	table := TableOf(rows,
+		Column[fruit]{Title: "Name", Value: func(f fruit) interface{} { return f.Name },
+			Footer: func([]fruit) Node { return T("Total") }},
+		Column[fruit]{Title: "Price", Value: func(f fruit) interface{} { return f.Price }, Format: price},
+		Column[fruit]{Title: "Qty", Value: func(f fruit) interface{} { return f.Qty },
+			Footer: func(rows []fruit) Node {
+				n := 0
+				for _, f := range rows {
+					n += f.Qty
+				}
+				return Tf("%d", n)
+			}},
+	).Caption(T("Fruits"))
+	fmt.Println(NodeToHTMLNode(table, RenderOptions{SortAttr: true}))
+	// OUTPUT:
+	// <table><caption>Fruits</caption><thead><tr><th scope="col">Name<th scope="col">Price<th scope="col">Qty<tbody><tr><td>Apple<td>$0.50<td>4<tr><td>Pear<td>$0.75<td>2<tfoot><tr><td>Total<td><td>6</table>
+}
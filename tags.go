@@ -2,6 +2,7 @@ package html
 
 import (
 	"strconv"
+	"strings"
 
 	. "github.com/gohtml/elements"
 	"github.com/gohtml/utils"
@@ -15,15 +16,20 @@ http://www.w3.org/TR/html5/semantics.html#the-root-element
 // HTML creates an HTML element with type Html.
 // http://www.w3.org/TR/html5/semantics.html#the-html-element
 func HTML(lang string) *Html {
-	return (&Html{
+	h := &Html{
 		Element: Element{
 			Void: Void{tagType: HTMLTag},
-			children: []Node{
-				HEAD(),
-				BODY(),
-			},
 		},
-	}).Lang(lang)
+	}
+	h.head.Charset("utf-8")
+	h.children = []Node{
+		&Element{
+			Void:     Void{tagType: HEADTag},
+			children: []Node{&h.head},
+		},
+		BODY(),
+	}
+	return h.Lang(lang)
 }
 
 /*
@@ -73,6 +79,17 @@ func LINK(href URL, rel string) *Void {
 	}).Attr("href", string(href)).Attr("rel", rel)
 }
 
+// STYLE creates a style element. Occurrences of "</" in css are escaped so
+// that the element can not be closed by its content.
+// http://www.w3.org/TR/html5/document-metadata.html#the-style-element
+func STYLE(css string) *Element {
+	t := &Element{Void: Void{tagType: STYLETag}}
+	if css != "" {
+		t.Child(HTMLNode(strings.Replace(css, "</", `<\/`, -1)))
+	}
+	return t
+}
+
 // The area element
 func AREA(href, alt string, shape string, coords []int) *Void {
 	area := (&Void{
@@ -487,3 +504,18 @@ func UL(children ...Node) *Element {
 		Void: Void{tagType: ULTag},
 	}).Child(children...)
 }
+
+// NewElement creates an element of any type, e.g. NewElement(ARTICLETag), for
+// elements without specific constructors.
+func NewElement(tp TagType, children ...Node) *Element {
+	return (&Element{
+		Void: Void{tagType: tp},
+	}).Child(children...)
+}
+
+// NewVoid creates a void element of any type, e.g. NewVoid(WBRTag).
+func NewVoid(tp TagType) *Void {
+	return &Void{
+		tagType: tp,
+	}
+}